@@ -0,0 +1,263 @@
+package form_builder
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+var errNotStructPointer = errors.New("form: only pointers to structs can be bound")
+
+// maxMemory is how much of a multipart form BindRequest keeps in memory.
+// Larger uploads are stored in temporary files.
+const maxMemory = 32 << 20
+
+// Bind sets the fields of dst, which must be a pointer to a struct or the
+// Data of a Definition, from submitted values. Values are looked up by the
+// same input names that HTML renders, so a form rendered from a struct
+// binds back into it.
+//
+// Submitted values that can't be converted to their field's type are
+// returned as FieldErrors, ready to be passed back to HTML. The error is
+// only non-nil if dst itself can't be bound to, such as when it isn't a
+// pointer to a struct or has a field of an unsupported type.
+//
+// Fields missing from values are left untouched, unless WithDefaults is
+// provided, in which case they are set from their default tag.
+//
+// Values are normalized by the transforms listed in a field's transform
+// tag before they are converted, so validation sees the cleaned up value:
+//
+//	Email string `form:"transform=trim,lower"`
+//
+// See RegisterTransform for the built-in transforms.
+func Bind(values url.Values, dst interface{}, opts ...Option) (FieldErrors, error) {
+	return bind(values, nil, dst, newConfig(opts))
+}
+
+// BindRequest is like Bind, but parses the submitted values from r. For
+// multipart forms, *multipart.FileHeader and []*multipart.FileHeader fields
+// are also bound from the uploaded files. The maxsize tag limits the size
+// of each file, and the accept tag limits their types, which are detected
+// from the files' contents:
+//
+//	Avatar *multipart.FileHeader `form:"maxsize=5MB;accept=image/png,image/jpeg"`
+//
+// Files that break these limits are left out and reported as FieldErrors.
+// An extension in the accept tag, such as .pdf, only matches files whose
+// contents are of the type registered for it too, so list content types for
+// formats that can't be detected, such as most text formats.
+//
+// The maxsize tag is checked once the whole request has been parsed, which
+// keeps up to 32MB of it in memory and the rest in temporary files. Limit
+// the size of request bodies with http.MaxBytesReader to stop larger
+// uploads from being read at all.
+func BindRequest(r *http.Request, dst interface{}, opts ...Option) (FieldErrors, error) {
+	err := parseRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if r.MultipartForm != nil {
+		files = r.MultipartForm.File
+	}
+	return bind(r.Form, files, dst, newConfig(opts))
+}
+
+// parseRequest parses r's form, including multipart forms.
+func parseRequest(r *http.Request) error {
+	err := r.ParseMultipartForm(maxMemory)
+	if err == http.ErrNotMultipart {
+		return nil
+	}
+	return err
+}
+
+func bind(values url.Values, files map[string][]*multipart.FileHeader, dst interface{}, cfg *config) (FieldErrors, error) {
+	if _, ok := dst.(leafWalker); !ok {
+		refVal := reflect.ValueOf(dst)
+		if refVal.Kind() != reflect.Ptr || refVal.IsNil() || refVal.Elem().Kind() != reflect.Struct {
+			return nil, errNotStructPointer
+		}
+	}
+
+	var ferrs FieldErrors
+	err := walkForm(dst, true, cfg, func(l leaf) error {
+		if isFile(l.value.Type()) {
+			fileErrs, err := bindFiles(l, files[l.name])
+			ferrs = append(ferrs, fileErrs...)
+			return err
+		}
+
+		fns, err := parseTransforms(l.tags["transform"])
+		if err != nil {
+			return fmt.Errorf("%v on field %s", err, l.field.Name)
+		}
+
+		raws, ok := values[l.name]
+		if !ok {
+			def, hasDefault := l.tags["default"]
+			if !cfg.defaults || !hasDefault || isCheckbox(l) {
+				return nil
+			}
+			raws = []string{def}
+		}
+		raws = applyTransforms(fns, raws)
+
+		err = setValues(l.value, raws)
+		var cerr *convertError
+		if errors.As(err, &cerr) {
+			if l.invalid != nil {
+				l.invalid()
+			}
+			ferrs = append(ferrs, FieldError{
+				Field:  l.name,
+				Code:   cerr.code,
+				Params: map[string]interface{}{"label": labelOf(l)},
+			})
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ferrs, nil
+}
+
+// isCheckbox reports whether l is a checkbox: a bool, or a field with
+// type=checkbox. Browsers leave unchecked checkboxes out of the values they
+// submit, so a missing value means unchecked rather than not given, and
+// must not be replaced by a default.
+func isCheckbox(l leaf) bool {
+	return l.tags["type"] == "checkbox" || valueOf(l.value).Kind() == reflect.Bool
+}
+
+// convertError is returned when a submitted value can't be converted to
+// the type of the field it is bound to.
+type convertError struct {
+	code string
+}
+
+func (e *convertError) Error() string {
+	return interpolate(defaultMessages[e.code], map[string]interface{}{"label": "value"})
+}
+
+// convert returns raw converted to a value of type t, using the same rules
+// as Bind.
+func convert(t reflect.Type, raw string) (interface{}, error) {
+	v := reflect.New(t).Elem()
+	err := setValues(v, []string{raw})
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// setValues sets v from every submitted value for a field. Slices get one
+// element per value; any other type is set from the first value.
+func setValues(v reflect.Value, raws []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 && !isTextUnmarshaler(v) {
+		s := reflect.MakeSlice(v.Type(), len(raws), len(raws))
+		for i, raw := range raws {
+			err := setValue(s.Index(i), raw)
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+
+	var raw string
+	if len(raws) > 0 {
+		raw = raws[0]
+	}
+	return setValue(v, raw)
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Ptr {
+		// An empty value for an optional field means no value at all.
+		if raw == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), raw)
+	}
+
+	if isTextUnmarshaler(v) {
+		err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+		if err != nil {
+			return &convertError{"invalid"}
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		switch raw {
+		case "":
+			v.SetBool(false)
+		case "on":
+			// Checkboxes without a value attribute submit "on".
+			v.SetBool(true)
+		default:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return &convertError{"not_bool"}
+			}
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if raw == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return &convertError{"not_integer"}
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if raw == "" {
+			v.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return &convertError{"not_integer"}
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if raw == "" {
+			v.SetFloat(0)
+			return nil
+		}
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return &convertError{"not_number"}
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("form: can't bind to a field of type %s", v.Type())
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func isTextUnmarshaler(v reflect.Value) bool {
+	return v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType)
+}
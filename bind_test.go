@@ -0,0 +1,192 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type signup struct {
+	Name    string
+	Email   string `form:"name=email"`
+	Age     int    `form:"label=Your age"`
+	Score   float64
+	Agree   bool
+	Nick    *string
+	Tags    []string
+	Address *struct {
+		Street string
+		Zip    uint16
+	}
+	Country  string `form:"default=US"`
+	Quantity int    `form:"default=1"`
+	private  string
+}
+
+func TestBind(t *testing.T) {
+	nick := "ally"
+
+	tests := map[string]struct {
+		values url.Values
+		opts   []form_builder.Option
+		want   signup
+		errors form_builder.FieldErrors
+	}{
+		"Values are converted to the field types": {
+			values: url.Values{
+				"Name":           {"Alice Smith"},
+				"email":          {"alice@cc.cc"},
+				"Age":            {"25"},
+				"Score":          {"9.5"},
+				"Agree":          {"on"},
+				"Nick":           {"ally"},
+				"Tags":           {"a", "b"},
+				"Address.Street": {"123 ABC St"},
+				"Address.Zip":    {"12345"},
+			},
+			want: signup{
+				Name:  "Alice Smith",
+				Email: "alice@cc.cc",
+				Age:   25,
+				Score: 9.5,
+				Agree: true,
+				Nick:  &nick,
+				Tags:  []string{"a", "b"},
+				Address: &struct {
+					Street string
+					Zip    uint16
+				}{"123 ABC St", 12345},
+			},
+		},
+		"Unconvertible values are returned as field errors": {
+			values: url.Values{
+				"Name":        {"Alice Smith"},
+				"Age":         {"abc"},
+				"Score":       {"1.2.3"},
+				"Agree":       {"maybe"},
+				"Address.Zip": {"99999"},
+			},
+			want: signup{
+				Name: "Alice Smith",
+				Address: &struct {
+					Street string
+					Zip    uint16
+				}{},
+			},
+			errors: form_builder.FieldErrors{
+				{Field: "Age", Code: "not_integer", Params: map[string]interface{}{"label": "Your age"}},
+				{Field: "Score", Code: "not_number", Params: map[string]interface{}{"label": "Score"}},
+				{Field: "Agree", Code: "not_bool", Params: map[string]interface{}{"label": "Agree"}},
+				{Field: "Address.Zip", Code: "not_integer", Params: map[string]interface{}{"label": "Zip"}},
+			},
+		},
+		"Defaults are only applied when asked for": {
+			values: url.Values{
+				"Quantity": {"3"},
+			},
+			want: signup{
+				Quantity: 3,
+				Address: &struct {
+					Street string
+					Zip    uint16
+				}{},
+			},
+		},
+		"Defaults are applied to missing fields": {
+			values: url.Values{
+				"Quantity": {"3"},
+			},
+			opts: []form_builder.Option{form_builder.WithDefaults()},
+			want: signup{
+				Country:  "US",
+				Quantity: 3,
+				Address: &struct {
+					Street string
+					Zip    uint16
+				}{},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got signup
+			errors, err := form_builder.Bind(tc.values, &got, tc.opts...)
+			if err != nil {
+				t.Fatalf("Bind() err = %v; want nil", err)
+			}
+			if !reflect.DeepEqual(errors, tc.errors) {
+				t.Errorf("Bind() errors = %v; want %v", errors, tc.errors)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Bind():\n  got %+v;\n want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBind_checkboxDefaults(t *testing.T) {
+	var dst struct {
+		Subscribe bool   `form:"default=true"`
+		Remember  string `form:"type=checkbox;default=yes"`
+		Country   string `form:"default=US"`
+	}
+	_, err := form_builder.Bind(url.Values{}, &dst, form_builder.WithDefaults())
+	if err != nil {
+		t.Fatalf("Bind() err = %v; want nil", err)
+	}
+	if dst.Subscribe || dst.Remember != "" {
+		t.Errorf("Bind() = %+v; want unchecked checkboxes left unset", dst)
+	}
+	if dst.Country != "US" {
+		t.Errorf("Bind() Country = %q; want the default", dst.Country)
+	}
+}
+
+func TestBind_textUnmarshaler(t *testing.T) {
+	var dst struct {
+		When  time.Time
+		Until time.Time
+	}
+	errors, err := form_builder.Bind(url.Values{
+		"When":  {"2024-05-01T10:00:00Z"},
+		"Until": {"tomorrow"},
+	}, &dst)
+	if err != nil {
+		t.Fatalf("Bind() err = %v; want nil", err)
+	}
+
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !dst.When.Equal(want) {
+		t.Errorf("Bind() When = %v; want %v", dst.When, want)
+	}
+	want := form_builder.FieldErrors{
+		{Field: "Until", Code: "invalid", Params: map[string]interface{}{"label": "Until"}},
+	}
+	if !reflect.DeepEqual(errors, want) {
+		t.Errorf("Bind() errors = %v; want %v", errors, want)
+	}
+}
+
+func TestBind_invalid(t *testing.T) {
+	tests := map[string]interface{}{
+		"not a pointer":    signup{},
+		"nil pointer":      (*signup)(nil),
+		"not a struct":     new(string),
+		"unsupported type": &struct{ Fn func() }{},
+		"invalid tag": &struct {
+			Name string `form:"invalid"`
+		}{},
+	}
+
+	for name, dst := range tests {
+		t.Run(name, func(t *testing.T) {
+			values := url.Values{"Fn": {"x"}}
+			_, err := form_builder.Bind(values, dst)
+			if err == nil {
+				t.Errorf("Bind() err = nil; want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package form_builder
+
+import "strconv"
+
+// Builder assembles a Definition in code, for forms that have no natural
+// struct:
+//
+//	def, err := form_builder.NewBuilder().
+//		Text("name", form_builder.FieldLabel("Name"), form_builder.FieldRequired()).
+//		Select("plan", form_builder.FieldChoices(
+//			form_builder.Choice{Value: "free", Label: "Free"},
+//			form_builder.Choice{Value: "pro", Label: "Pro"},
+//		)).
+//		Group("address", form_builder.NewBuilder().Text("street").Text("city")).
+//		Build()
+//
+// The fields of a struct can be mixed in with Struct.
+type Builder struct {
+	fields []FieldDefinition
+}
+
+// FieldOption sets a property of a field added to a Builder.
+type FieldOption func(*FieldDefinition)
+
+// NewBuilder returns a Builder with no fields.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Field adds an input of type typ called name.
+func (b *Builder) Field(typ, name string, opts ...FieldOption) *Builder {
+	fd := FieldDefinition{Name: name, Type: typ}
+	for _, opt := range opts {
+		opt(&fd)
+	}
+	b.fields = append(b.fields, fd)
+	return b
+}
+
+// Text adds a text input.
+func (b *Builder) Text(name string, opts ...FieldOption) *Builder {
+	return b.Field("", name, opts...)
+}
+
+// Email adds an email input.
+func (b *Builder) Email(name string, opts ...FieldOption) *Builder {
+	return b.Field("email", name, opts...)
+}
+
+// Password adds a password input.
+func (b *Builder) Password(name string, opts ...FieldOption) *Builder {
+	return b.Field("password", name, opts...)
+}
+
+// Number adds a number input, whose value is a float64.
+func (b *Builder) Number(name string, opts ...FieldOption) *Builder {
+	return b.Field("number", name, opts...)
+}
+
+// Checkbox adds a checkbox, whose value is a bool.
+func (b *Builder) Checkbox(name string, opts ...FieldOption) *Builder {
+	return b.Field("checkbox", name, opts...)
+}
+
+// Hidden adds a hidden input.
+func (b *Builder) Hidden(name string, opts ...FieldOption) *Builder {
+	return b.Field("hidden", name, opts...)
+}
+
+// File adds a file input, whose value is a *multipart.FileHeader.
+func (b *Builder) File(name string, opts ...FieldOption) *Builder {
+	return b.Field("file", name, opts...)
+}
+
+// Select adds a select. Its choices are set with FieldChoices.
+func (b *Builder) Select(name string, opts ...FieldOption) *Builder {
+	return b.Field("", name, opts...)
+}
+
+// Group adds the fields of group, with their names prefixed by name just as
+// the fields of a nested struct are.
+func (b *Builder) Group(name string, group *Builder) *Builder {
+	b.fields = append(b.fields, FieldDefinition{Name: name, Fields: append([]FieldDefinition{}, group.fields...)})
+	return b
+}
+
+// Struct adds the fields of strct, a struct or a pointer to one, as if it
+// were embedded in the form. Pass a pointer for the fields to be bound.
+// Validate calls the FormValidator and ContextFormValidator methods of
+// strct and its nested structs, just as it does for a struct on its own.
+func (b *Builder) Struct(strct interface{}) *Builder {
+	b.fields = append(b.fields, FieldDefinition{Struct: strct})
+	return b
+}
+
+// Build returns the Definition of the form, or an error if any of its
+// fields is malformed, just like ParseDefinition.
+func (b *Builder) Build() (*Definition, error) {
+	d := &Definition{Fields: append([]FieldDefinition(nil), b.fields...)}
+	err := d.check()
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// FieldLabel sets the label of a field.
+func FieldLabel(label string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Label = label
+	}
+}
+
+// FieldPlaceholder sets the placeholder of a field.
+func FieldPlaceholder(placeholder string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Placeholder = placeholder
+	}
+}
+
+// FieldHelp sets the help text of a field.
+func FieldHelp(help string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Help = help
+	}
+}
+
+// FieldChoices sets the choices of a select.
+func FieldChoices(choices ...Choice) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Options = append(fd.Options, choices...)
+	}
+}
+
+// FieldMultiple makes a field take more than one value.
+func FieldMultiple() FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Multiple = true
+	}
+}
+
+// FieldDefault sets the value of a field when it has none.
+func FieldDefault(value interface{}) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Default = value
+	}
+}
+
+// FieldRequired makes a field required.
+func FieldRequired() FieldOption {
+	return FieldRules("required")
+}
+
+// FieldMin sets the minimum length of a string or slice, or the minimum
+// value of a number.
+func FieldMin(n float64) FieldOption {
+	return FieldRules("min:" + strconv.FormatFloat(n, 'g', -1, 64))
+}
+
+// FieldMax sets the maximum length of a string or slice, or the maximum
+// value of a number.
+func FieldMax(n float64) FieldOption {
+	return FieldRules("max:" + strconv.FormatFloat(n, 'g', -1, 64))
+}
+
+// FieldRules adds validation rules to a field, written as they are in a
+// validate tag, such as "email" or "min:3", including any added with
+// RegisterValidator.
+func FieldRules(rules ...string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Validate = append(fd.Validate, rules...)
+	}
+}
+
+// FieldTransform adds transforms to a field, such as "trim".
+func FieldTransform(names ...string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Transform = append(fd.Transform, names...)
+	}
+}
+
+// FieldAccept sets the types of file a file input takes.
+func FieldAccept(accept string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.Accept = accept
+	}
+}
+
+// FieldMaxSize sets the largest file a file input takes, such as "5MB".
+func FieldMaxSize(size string) FieldOption {
+	return func(fd *FieldDefinition) {
+		fd.MaxSize = size
+	}
+}
@@ -0,0 +1,151 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	def, err := form_builder.NewBuilder().
+		Email("Email", form_builder.FieldLabel("Email address"), form_builder.FieldRequired(), form_builder.FieldRules("email"), form_builder.FieldTransform("trim")).
+		Select("Plan", form_builder.FieldChoices(
+			form_builder.Choice{Value: "free", Label: "Free"},
+			form_builder.Choice{Value: "pro", Label: "Pro"},
+		), form_builder.FieldDefault("free")).
+		Select("Topics", form_builder.FieldChoices(form_builder.Choice{Value: "go"}, form_builder.Choice{Value: "web"}), form_builder.FieldMultiple()).
+		Number("Rating", form_builder.FieldHelp("From 1 to 5"), form_builder.FieldMin(1), form_builder.FieldMax(5)).
+		Group("Address", form_builder.NewBuilder().
+			Text("Street", form_builder.FieldPlaceholder("123 Main St")).
+			Text("City")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() err = %v; want nil", err)
+	}
+
+	want, err := form_builder.ParseDefinition([]byte(surveyJSON))
+	if err != nil {
+		t.Fatalf("ParseDefinition() err = %v; want nil", err)
+	}
+	gotFields, err := form_builder.Fields(def)
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+	wantFields, err := form_builder.Fields(&survey{})
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+	if !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf("Fields(builder) = %+v; want the same as the struct: %+v", gotFields, wantFields)
+	}
+	if !reflect.DeepEqual(def, want) {
+		t.Errorf("Build() = %+v; want the same as the JSON definition: %+v", def, want)
+	}
+}
+
+func TestBuilder_Struct(t *testing.T) {
+	type address struct {
+		Street string `form:"validate=required"`
+		City   string
+	}
+
+	var addr address
+	def, err := form_builder.NewBuilder().
+		Text("Note").
+		Group("Shipping", form_builder.NewBuilder().Struct(&addr)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() err = %v; want nil", err)
+	}
+
+	values := make(map[string]interface{})
+	errors, err := form_builder.Bind(url.Values{
+		"Note":          {"Leave at the door"},
+		"Shipping.City": {"Porto"},
+	}, def.Data(values))
+	if err != nil || len(errors) > 0 {
+		t.Fatalf("Bind() = %v, %v; want no errors", errors, err)
+	}
+
+	wantValues := map[string]interface{}{
+		"Note":     "Leave at the door",
+		"Shipping": map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("Bind() values = %v; want %v", values, wantValues)
+	}
+	if addr != (address{City: "Porto"}) {
+		t.Errorf("Bind() struct = %+v; want City set", addr)
+	}
+
+	errors, err = form_builder.Validate(def.Data(values))
+	if err != nil {
+		t.Fatalf("Validate() err = %v; want nil", err)
+	}
+	wantErrors := form_builder.FieldErrors{
+		{Field: "Shipping.Street", Code: "required", Params: map[string]interface{}{"label": "Street"}},
+	}
+	if !reflect.DeepEqual(errors, wantErrors) {
+		t.Errorf("Validate() = %v; want %v", errors, wantErrors)
+	}
+}
+
+type passwords struct {
+	Password string `form:"type=password"`
+	Confirm  string `form:"type=password"`
+}
+
+func (p *passwords) ValidateForm() []form_builder.FieldError {
+	if p.Password != p.Confirm {
+		return []form_builder.FieldError{{Field: "Confirm", Code: "mismatch"}}
+	}
+	return nil
+}
+
+func TestBuilder_StructValidateForm(t *testing.T) {
+	var pw passwords
+	def, err := form_builder.NewBuilder().
+		Text("Name").
+		Group("Account", form_builder.NewBuilder().Struct(&pw)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() err = %v; want nil", err)
+	}
+
+	values := make(map[string]interface{})
+	_, err = form_builder.Bind(url.Values{
+		"Account.Password": {"secret123"},
+		"Account.Confirm":  {"secret124"},
+	}, def.Data(values))
+	if err != nil {
+		t.Fatalf("Bind() err = %v; want nil", err)
+	}
+
+	errors, err := form_builder.Validate(def.Data(values))
+	if err != nil {
+		t.Fatalf("Validate() err = %v; want nil", err)
+	}
+	want := form_builder.FieldErrors{{Field: "Account.Confirm", Code: "mismatch"}}
+	if !reflect.DeepEqual(errors, want) {
+		t.Errorf("Validate() = %v; want %v", errors, want)
+	}
+}
+
+func TestBuilder_invalid(t *testing.T) {
+	_, err := form_builder.NewBuilder().Number("Age", form_builder.FieldDefault("old")).Build()
+	if err == nil {
+		t.Errorf("Build() err = nil; want an error")
+	}
+}
+
+func TestBuilder_duplicateStructName(t *testing.T) {
+	var contact struct {
+		Phone string `form:"name=Email"`
+	}
+	_, err := form_builder.NewBuilder().Email("Email").Struct(&contact).Build()
+	if err == nil || !strings.Contains(err.Error(), "Email and Phone") {
+		t.Errorf("Build() err = %v; want it to name both fields", err)
+	}
+}
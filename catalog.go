@@ -0,0 +1,271 @@
+package form_builder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Catalog holds translated messages for any number of locales. Messages
+// are looked up by key, such as "user.email" for a label=@user.email tag or
+// "required" for a FieldError Code, and may refer to parameters as {name}.
+type Catalog struct {
+	// Fallback is the locale used for keys missing from the requested one.
+	Fallback string
+
+	messages map[string]map[string]string
+}
+
+// NewCatalog returns an empty Catalog that falls back to the fallback
+// locale.
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{
+		Fallback: fallback,
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// LoadCatalog loads every .json and .toml file at the root of fsys into a
+// new Catalog. Each file holds the messages for the locale it is named
+// after, such as en.json or pt-BR.toml. Nested JSON objects and TOML tables
+// are flattened into dotted keys, so {"user": {"email": "Email"}} defines
+// the key "user.email".
+func LoadCatalog(fsys fs.FS, fallback string) (*Catalog, error) {
+	c := NewCatalog(fallback)
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		messages := make(map[string]string)
+		switch ext {
+		case ".json":
+			err = parseJSONMessages(data, messages)
+		case ".toml":
+			err = parseTOMLMessages(data, messages)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("form: loading %s: %v", entry.Name(), err)
+		}
+		c.Add(strings.TrimSuffix(entry.Name(), ext), messages)
+	}
+	return c, nil
+}
+
+// Add adds messages to the catalog for locale, replacing any existing
+// messages with the same keys.
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string)
+	}
+	for k, v := range messages {
+		c.messages[locale][k] = v
+	}
+}
+
+// Translator returns a Translator for the first of locales the catalog has
+// messages for. A locale such as "fr-CA" also matches messages for "fr".
+// Keys missing from that locale are looked up in the Fallback locale.
+func (c *Catalog) Translator(locales ...string) Translator {
+	chain := make([]map[string]string, 0, 2)
+	for _, locale := range locales {
+		if messages := c.lookup(locale); messages != nil {
+			chain = append(chain, messages)
+			break
+		}
+	}
+	if messages := c.lookup(c.Fallback); messages != nil {
+		chain = append(chain, messages)
+	}
+
+	return TranslatorFunc(func(key string, params map[string]interface{}) string {
+		for _, messages := range chain {
+			if msg, ok := messages[key]; ok {
+				return interpolate(msg, params)
+			}
+		}
+		return ""
+	})
+}
+
+// TranslatorFor returns a Translator for the locales listed in r's
+// Accept-Language header, in order of preference.
+func (c *Catalog) TranslatorFor(r *http.Request) Translator {
+	return c.Translator(acceptLanguages(r.Header.Get("Accept-Language"))...)
+}
+
+func (c *Catalog) lookup(locale string) map[string]string {
+	locale = normalizeLocale(locale)
+	if messages, ok := c.messages[locale]; ok {
+		return messages
+	}
+	if i := strings.Index(locale, "-"); i > 0 {
+		return c.messages[locale[:i]]
+	}
+	return nil
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
+
+// acceptLanguages returns the languages in an Accept-Language header,
+// ordered by their quality value.
+func acceptLanguages(header string) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		l := lang{tag: strings.TrimSpace(fields[0]), q: 1}
+		if l.tag == "" || l.tag == "*" {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err == nil {
+					l.q = q
+				}
+			}
+		}
+		if l.q > 0 {
+			langs = append(langs, l)
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+func parseJSONMessages(data []byte, messages map[string]string) error {
+	var raw map[string]interface{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	return flatten("", raw, messages)
+}
+
+func flatten(prefix string, raw map[string]interface{}, messages map[string]string) error {
+	for k, v := range raw {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			messages[key] = v
+		case map[string]interface{}:
+			err := flatten(key, v, messages)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q is not a string", key)
+		}
+	}
+	return nil
+}
+
+// parseTOMLMessages parses the subset of TOML needed for message files:
+// comments, [table] headers and key = "string" pairs.
+func parseTOMLMessages(data []byte, messages map[string]string) error {
+	var table string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			table = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return fmt.Errorf("line %d: expected key = value", n)
+		}
+		key := strings.Trim(strings.TrimSpace(line[:i]), `"`)
+		value, err := parseTOMLString(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+		if table != "" {
+			key = table + "." + key
+		}
+		messages[key] = value
+	}
+	return scanner.Err()
+}
+
+func parseTOMLString(s string) (string, error) {
+	end := closingQuote(s)
+	if end < 0 {
+		return "", fmt.Errorf("value %s is not a string", s)
+	}
+	// Only a comment may follow the closing quote.
+	if rest := strings.TrimSpace(s[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("value %s is not a string", s)
+	}
+	s = s[:end+1]
+
+	if s[0] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+// closingQuote returns the index of the quote closing the string s starts
+// with, skipping escaped quotes in basic strings, or -1 if s doesn't start
+// with a closed string.
+func closingQuote(s string) int {
+	if len(s) == 0 {
+		return -1
+	}
+	switch s[0] {
+	case '\'':
+		// Literal strings have no escapes.
+		if i := strings.IndexByte(s[1:], '\''); i >= 0 {
+			return i + 1
+		}
+	case '"':
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,171 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+var catalogFS = fstest.MapFS{
+	"en.json": {Data: []byte(`{
+		"required": "{label} is required",
+		"user": {"email": "Email", "email_help": "We'll never share it"}
+	}`)},
+	"fr.toml": {Data: []byte(`
+# French messages
+required = "{label} est obligatoire"
+
+[user]
+email = "Adresse e-mail" # inline comment
+greeting = "Bonjour" # dites "salut"
+quote = "Il a dit \"oui\"" # "guillemets"
+path = 'C:\temp' # 'littérale'
+`)},
+	"README.md": {Data: []byte(`not a catalog`)},
+}
+
+func TestCatalog_Translator(t *testing.T) {
+	c, err := form_builder.LoadCatalog(catalogFS, "en")
+	if err != nil {
+		t.Fatalf("LoadCatalog() err = %v; want nil", err)
+	}
+
+	tests := map[string]struct {
+		locales []string
+		key     string
+		params  map[string]interface{}
+		want    string
+	}{
+		"Fallback locale": {
+			key:  "user.email",
+			want: "Email",
+		},
+		"Requested locale": {
+			locales: []string{"fr"},
+			key:     "user.email",
+			want:    "Adresse e-mail",
+		},
+		"Regional locales match their language": {
+			locales: []string{"fr_CA"},
+			key:     "user.email",
+			want:    "Adresse e-mail",
+		},
+		"First known locale wins": {
+			locales: []string{"de", "fr", "en"},
+			key:     "user.email",
+			want:    "Adresse e-mail",
+		},
+		"Missing keys fall back": {
+			locales: []string{"fr"},
+			key:     "user.email_help",
+			want:    "We'll never share it",
+		},
+		"Quotes in trailing comments": {
+			locales: []string{"fr"},
+			key:     "user.greeting",
+			want:    "Bonjour",
+		},
+		"Escaped quotes": {
+			locales: []string{"fr"},
+			key:     "user.quote",
+			want:    `Il a dit "oui"`,
+		},
+		"Literal strings": {
+			locales: []string{"fr"},
+			key:     "user.path",
+			want:    `C:\temp`,
+		},
+		"Params are interpolated": {
+			locales: []string{"fr"},
+			key:     "required",
+			params:  map[string]interface{}{"label": "Nom"},
+			want:    "Nom est obligatoire",
+		},
+		"Unknown keys": {
+			key:  "nope",
+			want: "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := c.Translator(tc.locales...).Translate(tc.key, tc.params)
+			if got != tc.want {
+				t.Errorf("Translate(%q) = %q; want %q", tc.key, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_TranslatorFor(t *testing.T) {
+	c, err := form_builder.LoadCatalog(catalogFS, "en")
+	if err != nil {
+		t.Fatalf("LoadCatalog() err = %v; want nil", err)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "de;q=0.9, fr-CH, en;q=0.8")
+
+	if got := c.TranslatorFor(r).Translate("user.email", nil); got != "Adresse e-mail" {
+		t.Errorf("Translate() = %q; want %q", got, "Adresse e-mail")
+	}
+}
+
+func TestLoadCatalog_invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"invalid JSON":    {"en.json": {Data: []byte(`{`)}},
+		"non-string JSON": {"en.json": {Data: []byte(`{"count": 1}`)}},
+		"invalid TOML":    {"en.toml": {Data: []byte(`count`)}},
+		"non-string TOML": {"en.toml": {Data: []byte(`count = 1`)}},
+		"unclosed TOML":   {"en.toml": {Data: []byte(`name = "Name \" # comment`)}},
+		"trailing TOML":   {"en.toml": {Data: []byte(`name = "Name" "Other"`)}},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := form_builder.LoadCatalog(fsys, "en")
+			if err == nil {
+				t.Errorf("LoadCatalog() err = nil; want an error")
+			}
+		})
+	}
+}
+
+func TestFields_localized(t *testing.T) {
+	c, err := form_builder.LoadCatalog(catalogFS, "en")
+	if err != nil {
+		t.Fatalf("LoadCatalog() err = %v; want nil", err)
+	}
+
+	strct := struct {
+		Email string `form:"label=@user.email;placeholder=@user.email;help=@user.email_help"`
+		Other string `form:"label=@user.missing"`
+	}{}
+
+	fs, err := form_builder.Fields(strct,
+		form_builder.WithTranslator(c.Translator("fr")),
+		form_builder.WithErrors(form_builder.FieldError{
+			Field:  "Email",
+			Code:   "required",
+			Params: map[string]interface{}{"label": "@user.email"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+
+	email := fs[0]
+	if email.Label != "Adresse e-mail" || email.Placeholder != "Adresse e-mail" {
+		t.Errorf("Label, Placeholder = %q, %q; want them translated", email.Label, email.Placeholder)
+	}
+	if email.Help != "We'll never share it" {
+		t.Errorf("Help = %q; want %q", email.Help, "We'll never share it")
+	}
+	if len(email.Errors) != 1 || email.Errors[0] != "Adresse e-mail est obligatoire" {
+		t.Errorf("Errors = %q; want the translated required message", email.Errors)
+	}
+	if fs[1].Label != "user.missing" {
+		t.Errorf("Label = %q; want the key for a missing translation", fs[1].Label)
+	}
+}
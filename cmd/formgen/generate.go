@@ -0,0 +1,845 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"form_builder"
+)
+
+// kinds maps the predeclared types formgen supports to their kind, which
+// decides how values are converted and measured.
+var kinds = map[string]string{
+	"string":  "string",
+	"bool":    "bool",
+	"int":     "int",
+	"int8":    "int",
+	"int16":   "int",
+	"int32":   "int",
+	"int64":   "int",
+	"rune":    "int",
+	"uint":    "uint",
+	"uint8":   "uint",
+	"uint16":  "uint",
+	"uint32":  "uint",
+	"uint64":  "uint",
+	"byte":    "uint",
+	"float32": "float",
+	"float64": "float",
+}
+
+// bits returns the size of the predeclared type basic, as reflect's Bits
+// does, for strconv.
+func bits(basic string) int {
+	switch basic {
+	case "int8", "uint8", "byte":
+		return 8
+	case "int16", "uint16":
+		return 16
+	case "int32", "uint32", "rune", "float32":
+		return 32
+	default:
+		return 64
+	}
+}
+
+// valueType is the type of a field that becomes an input.
+type valueType struct {
+	ptr   bool
+	slice bool
+	// elem is the type of the field's values as written, such as "int" or
+	// "Status", and basic the predeclared type it is based on.
+	elem  string
+	basic string
+	// helper is set if elem implements form_builder.FormHelper.
+	helper bool
+}
+
+func (t valueType) kind() string {
+	return kinds[t.basic]
+}
+
+// zero returns the zero value of t's values, for comparisons.
+func (t valueType) zero() string {
+	switch t.kind() {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	default:
+		return "0"
+	}
+}
+
+type rule struct {
+	name string
+	arg  float64
+}
+
+// field is a single input, with everything the reflection path would work
+// out about it at run time.
+type field struct {
+	goName string
+	path   string
+	name   string
+	// expr is the expression for the field's value, such as s.Address.Zip.
+	expr    string
+	typ     valueType
+	tags    map[string]string
+	rules   []rule
+	choices []form_builder.Choice
+}
+
+func (f field) label() string {
+	if v, ok := f.tags["label"]; ok {
+		return v
+	}
+	return f.goName
+}
+
+// bindDefault returns the default Bind falls back to when f isn't
+// submitted. Checkboxes have none, since browsers leave them out when
+// they are unchecked.
+func (f field) bindDefault() (string, bool) {
+	if f.tags["type"] == "checkbox" || f.typ.kind() == "bool" && !f.typ.slice {
+		return "", false
+	}
+	def, ok := f.tags["default"]
+	return def, ok
+}
+
+// hook is a struct whose ValidateForm or ValidateFormContext methods are
+// called by Validate.
+type hook struct {
+	expr        string
+	prefix      string
+	form        bool
+	formContext bool
+}
+
+type generator struct {
+	pkg     string
+	types   map[string]*ast.TypeSpec
+	methods map[string]map[string]bool
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+// generate returns the source of the methods for the types called names,
+// declared in the package in dir. The file called output is ignored, so
+// generating again isn't affected by the code generated last time.
+func generate(dir string, names []string, output string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("formgen: found %d packages in %s; want 1", len(pkgs), dir)
+	}
+
+	g := &generator{
+		types:   make(map[string]*ast.TypeSpec),
+		methods: make(map[string]map[string]bool),
+		imports: map[string]bool{"form_builder": true},
+	}
+	for _, pkg := range pkgs {
+		g.pkg = pkg.Name
+		g.collect(pkg)
+	}
+
+	var body bytes.Buffer
+	for _, name := range names {
+		g.buf.Reset()
+		err := g.generateType(name)
+		if err != nil {
+			return nil, err
+		}
+		body.Write(g.buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by formgen -type=%s; DO NOT EDIT.\n\n", strings.Join(names, ","))
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", g.pkg)
+	var imports []string
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	for _, imp := range imports {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	fmt.Fprintf(&out, ")\n")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formgen: generated invalid code: %v", err)
+	}
+	return src, nil
+}
+
+// collect records the types and methods declared in pkg.
+func (g *generator) collect(pkg *ast.Package) {
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						g.types[ts.Name.Name] = ts
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) != 1 {
+					continue
+				}
+				recv := decl.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok {
+					if g.methods[ident.Name] == nil {
+						g.methods[ident.Name] = make(map[string]bool)
+					}
+					g.methods[ident.Name][decl.Name.Name] = true
+				}
+			}
+		}
+	}
+}
+
+func (g *generator) generateType(name string) error {
+	ts, ok := g.types[name]
+	if !ok {
+		return fmt.Errorf("formgen: type %s not found", name)
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("formgen: %s is not a struct type", name)
+	}
+
+	fields, hooks, err := g.walk(st, "s", nil)
+	if err != nil {
+		return fmt.Errorf("formgen: %s.%v", name, err)
+	}
+	paths := make(map[string]string)
+	for _, f := range fields {
+		if prev, ok := paths[f.name]; ok {
+			return fmt.Errorf("formgen: %s: duplicate input name %q on fields %s and %s", name, f.name, prev, f.path)
+		}
+		paths[f.name] = f.path
+	}
+	hooks = append([]hook{g.hookFor(name, "s", nil)}, hooks...)
+
+	g.fieldsMethod(name, fields)
+	g.bindMethod(name, fields)
+	g.validateMethod(name, fields, hooks)
+	return nil
+}
+
+func (g *generator) hookFor(typeName, expr string, names []string) hook {
+	return hook{
+		expr:        expr,
+		prefix:      strings.Join(names, "."),
+		form:        g.methods[typeName]["ValidateForm"],
+		formContext: g.methods[typeName]["ValidateFormContext"],
+	}
+}
+
+// walk returns the inputs of st, whose value is expr and which is nested at
+// the Go field path names, in the same order as the reflection path, along
+// with the structs nested in it that have validation hooks.
+func (g *generator) walk(st *ast.StructType, expr string, names []string) ([]field, []hook, error) {
+	var fields []field
+	var hooks []hook
+	for _, f := range st.Fields.List {
+		goNames := f.Names
+		if len(goNames) == 0 {
+			// Embedded fields are named after their type.
+			typ := f.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			ident, ok := typ.(*ast.Ident)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: embedded %s is not supported", strings.Join(names, "."), types(f.Type))
+			}
+			goNames = []*ast.Ident{ident}
+		}
+
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, nil, err
+			}
+			tag = reflect.StructTag(raw)
+		}
+
+		for _, ident := range goNames {
+			if !ident.IsExported() {
+				continue
+			}
+			fieldNames := append(names[:len(names):len(names)], ident.Name)
+			fieldExpr := expr + "." + ident.Name
+			path := strings.Join(fieldNames, ".")
+
+			nested, typeName, err := g.structOf(f.Type)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %v", path, err)
+			}
+			if nested != nil {
+				if typeName != "" {
+					hooks = append(hooks, g.hookFor(typeName, fieldExpr, fieldNames))
+				}
+				nestedFields, nestedHooks, err := g.walk(nested, fieldExpr, fieldNames)
+				if err != nil {
+					return nil, nil, err
+				}
+				fields = append(fields, nestedFields...)
+				hooks = append(hooks, nestedHooks...)
+				continue
+			}
+
+			fd, err := g.field(ident.Name, path, fieldExpr, f.Type, tag.Get("form"))
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %v", path, err)
+			}
+			fields = append(fields, fd)
+		}
+	}
+	return fields, hooks, nil
+}
+
+// structOf returns the struct type expr refers to, and its name if it has
+// one, or nil if it isn't a struct.
+func (g *generator) structOf(expr ast.Expr) (*ast.StructType, string, error) {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t, "", nil
+	case *ast.Ident:
+		if ts, ok := g.types[t.Name]; ok {
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				return st, t.Name, nil
+			}
+		}
+	case *ast.StarExpr:
+		st, _, err := g.structOf(t.X)
+		if err != nil {
+			return nil, "", err
+		}
+		if st != nil {
+			return nil, "", fmt.Errorf("pointers to structs are not supported")
+		}
+	}
+	return nil, "", nil
+}
+
+func (g *generator) field(goName, path, expr string, typExpr ast.Expr, rawTag string) (field, error) {
+	tags, err := form_builder.ParseTag(rawTag)
+	if err != nil {
+		return field{}, err
+	}
+	typ, err := g.valueType(typExpr)
+	if err != nil {
+		return field{}, err
+	}
+
+	f := field{goName: goName, path: path, name: path, expr: expr, typ: typ, tags: tags}
+	if v, ok := tags["name"]; ok {
+		f.name = v
+	}
+
+	if v, ok := tags["validate"]; ok && v != "" {
+		for _, s := range strings.Split(v, ",") {
+			r, err := parseRule(s, typ)
+			if err != nil {
+				return field{}, err
+			}
+			f.rules = append(f.rules, r)
+		}
+	}
+
+	if v, ok := tags["options"]; ok {
+		for _, s := range strings.Split(v, ",") {
+			c := form_builder.Choice{Value: s, Label: s}
+			if i := strings.Index(s, ":"); i >= 0 {
+				c.Value, c.Label = s[:i], s[i+1:]
+			}
+			_, err := literal(typ, c.Value)
+			if err != nil {
+				return field{}, fmt.Errorf("invalid option %q: %v", c.Value, err)
+			}
+			f.choices = append(f.choices, c)
+		}
+	}
+
+	if v, ok := tags["default"]; ok {
+		if typ.slice {
+			return field{}, fmt.Errorf("defaults are not supported on slices")
+		}
+		_, err := literal(typ, v)
+		if err != nil {
+			return field{}, fmt.Errorf("invalid default %q: %v", v, err)
+		}
+	}
+	return f, nil
+}
+
+func parseRule(s string, typ valueType) (rule, error) {
+	name, arg := s, ""
+	if i := strings.Index(s, ":"); i >= 0 {
+		name, arg = s[:i], s[i+1:]
+	}
+
+	switch name {
+	case "required":
+		return rule{name: name}, nil
+	case "email":
+		if typ.slice || typ.kind() != "string" {
+			return rule{}, fmt.Errorf("rule email can't be applied to %s", typ.elem)
+		}
+		return rule{name: name}, nil
+	case "min", "max":
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return rule{}, fmt.Errorf("rule %s: %q is not a number", name, arg)
+		}
+		if !typ.slice && typ.kind() == "bool" {
+			return rule{}, fmt.Errorf("rule %s can't be applied to %s", name, typ.elem)
+		}
+		return rule{name: name, arg: n}, nil
+	default:
+		return rule{}, fmt.Errorf("rule %q is not supported; only required, min, max and email can be generated", name)
+	}
+}
+
+// valueType resolves the type of a field that becomes an input.
+func (g *generator) valueType(expr ast.Expr) (valueType, error) {
+	var t valueType
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		t.ptr = true
+		expr = e.X
+	case *ast.ArrayType:
+		if e.Len != nil {
+			return t, fmt.Errorf("arrays are not supported")
+		}
+		t.slice = true
+		expr = e.Elt
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return t, fmt.Errorf("type %s is not supported", types(expr))
+	}
+	t.elem = ident.Name
+	basic, err := g.basicOf(ident.Name)
+	if err != nil {
+		return t, err
+	}
+	t.basic = basic
+	if t.slice && t.kind() == "uint" && bits(basic) == 8 {
+		return t, fmt.Errorf("type []%s is not supported", t.elem)
+	}
+	t.helper = g.methods[ident.Name]["FormHelp"]
+	return t, nil
+}
+
+// basicOf returns the predeclared type the type called name is based on.
+func (g *generator) basicOf(name string) (string, error) {
+	if _, ok := kinds[name]; ok {
+		return name, nil
+	}
+
+	ts, ok := g.types[name]
+	if !ok {
+		return "", fmt.Errorf("type %s is not supported", name)
+	}
+	for _, m := range []string{"UnmarshalText", "MarshalText"} {
+		if g.methods[name][m] {
+			return "", fmt.Errorf("type %s implements encoding.TextUnmarshaler or TextMarshaler, which is not supported", name)
+		}
+	}
+	ident, ok := ts.Type.(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("type %s is not supported", name)
+	}
+	return g.basicOf(ident.Name)
+}
+
+// types formats a type expression for error messages.
+func types(expr ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+// literal returns the Go literal for raw converted to a value of t, using
+// the same rules as form_builder.Bind.
+func literal(t valueType, raw string) (string, error) {
+	switch t.kind() {
+	case "string":
+		return strconv.Quote(raw), nil
+	case "bool":
+		switch raw {
+		case "":
+			return "false", nil
+		case "on":
+			return "true", nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case "int":
+		if raw == "" {
+			return "0", nil
+		}
+		n, err := strconv.ParseInt(raw, 10, bits(t.basic))
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	case "uint":
+		if raw == "" {
+			return "0", nil
+		}
+		n, err := strconv.ParseUint(raw, 10, bits(t.basic))
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(n, 10), nil
+	default:
+		if raw == "" {
+			return "0", nil
+		}
+		n, err := strconv.ParseFloat(raw, bits(t.basic))
+		if err != nil {
+			return "", err
+		}
+		s := strconv.FormatFloat(n, 'g', -1, bits(t.basic))
+		if _, err := strconv.ParseFloat(s, 64); err != nil || strings.ContainsAny(s, "NI") {
+			return "", fmt.Errorf("%q can't be generated", raw)
+		}
+		return s, nil
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// value declares value as the value of f, with pointers dereferenced as the
+// reflection path does.
+func (g *generator) value(f field) {
+	if f.typ.ptr {
+		g.printf("var value %s\n", f.typ.elem)
+		g.printf("if %s != nil { value = *%s }\n", f.expr, f.expr)
+		return
+	}
+	g.printf("value := %s\n", f.expr)
+}
+
+// isZero returns the condition for value being the zero value.
+func isZero(f field) string {
+	if f.typ.slice {
+		return "value == nil"
+	}
+	return "value == " + f.typ.zero()
+}
+
+func (g *generator) fieldsMethod(name string, fields []field) {
+	g.printf("\n// Fields is like form_builder.Fields, without reflection.\n")
+	g.printf("func (s *%s) Fields(opts ...form_builder.Option) ([]form_builder.Field, error) {\n", name)
+	g.printf("fs := make([]form_builder.Field, 0, %d)\n", len(fields))
+	for _, f := range fields {
+		typ := "text"
+		if f.choices != nil {
+			typ = "select"
+		}
+		placeholder := f.goName
+		if v, ok := f.tags["placeholder"]; ok {
+			placeholder = v
+		}
+		if v, ok := f.tags["type"]; ok {
+			typ = v
+		}
+
+		g.printf("{\n")
+		g.value(f)
+		g.printf("f := form_builder.Field{\n")
+		g.printf("Label: %q,\nName: %q,\nType: %q,\nPlaceholder: %q,\n", f.label(), f.name, typ, placeholder)
+		if v, ok := f.tags["accept"]; ok {
+			g.printf("Accept: %q,\n", v)
+		}
+		if f.choices != nil && f.typ.slice {
+			g.printf("Multiple: true,\n")
+		}
+		if f.choices != nil {
+			g.printf("Options: []form_builder.Choice{\n")
+			for _, c := range f.choices {
+				g.printf("{Value: %q, Label: %q},\n", c.Value, c.Label)
+			}
+			g.printf("},\n")
+		}
+		g.printf("}\n")
+		if v, ok := f.tags["help"]; ok {
+			g.printf("f.Help = %q\n", v)
+		} else if f.typ.helper && !f.typ.slice {
+			g.printf("f.Help = value.FormHelp()\n")
+		}
+		if v, ok := f.tags["default"]; ok {
+			lit, _ := literal(f.typ, v)
+			g.printf("if %s { value = %s }\n", isZero(f), lit)
+		}
+		g.printf("f.Value = value\n")
+		g.printf("fs = append(fs, f)\n")
+		g.printf("}\n")
+	}
+	g.printf("return form_builder.ResolveFields(fs, opts...), nil\n")
+	g.printf("}\n")
+}
+
+// fieldError returns the statement adding an error with code for f.
+func fieldError(f field, code string, params ...string) string {
+	params = append(params, fmt.Sprintf("%q: %q", "label", f.label()))
+	return fmt.Sprintf("ferrs = append(ferrs, form_builder.FieldError{Field: %q, Code: %s, Params: map[string]interface{}{%s}})\n",
+		f.name, code, strings.Join(params, ", "))
+}
+
+// convert sets target from raw, or sets code if raw can't be converted,
+// like setValue.
+func (g *generator) convert(t valueType, target string) {
+	conversion := func(v string) string {
+		if t.elem == t.basic && (t.kind() == "string" || t.kind() == "bool") {
+			return v
+		}
+		return t.elem + "(" + v + ")"
+	}
+
+	switch t.kind() {
+	case "string":
+		g.printf("%s = %s\n", target, conversion("raw"))
+	case "bool":
+		g.imports["strconv"] = true
+		g.printf("switch raw {\ncase \"\":\n%s = false\ncase \"on\":\n%s = true\ndefault:\n", target, target)
+		g.printf("if b, err := strconv.ParseBool(raw); err != nil { code = \"not_bool\" } else { %s = %s }\n", target, conversion("b"))
+		g.printf("}\n")
+	case "int", "uint":
+		g.imports["strconv"] = true
+		parse := "ParseInt"
+		if t.kind() == "uint" {
+			parse = "ParseUint"
+		}
+		g.printf("if raw == \"\" { %s = 0 } else if n, err := strconv.%s(raw, 10, %d); err != nil { code = \"not_integer\" } else { %s = %s }\n",
+			target, parse, bits(t.basic), target, conversion("n"))
+	default:
+		g.imports["strconv"] = true
+		g.printf("if raw == \"\" { %s = 0 } else if n, err := strconv.ParseFloat(raw, %d); err != nil { code = \"not_number\" } else { %s = %s }\n",
+			target, bits(t.basic), target, conversion("n"))
+	}
+}
+
+func (g *generator) bindMethod(name string, fields []field) {
+	g.imports["net/url"] = true
+
+	hasDefaults := false
+	for _, f := range fields {
+		if _, ok := f.bindDefault(); ok {
+			hasDefaults = true
+		}
+	}
+
+	g.printf("\n// Bind is like form_builder.Bind, without reflection.\n")
+	g.printf("func (s *%s) Bind(values url.Values, opts ...form_builder.Option) (form_builder.FieldErrors, error) {\n", name)
+	if hasDefaults {
+		g.printf("defaults := form_builder.AppliesDefaults(opts...)\n")
+	}
+	g.printf("var ferrs form_builder.FieldErrors\n")
+	for _, f := range fields {
+		g.printf("if raws, ok := values[%q]; ", f.name)
+		if def, ok := f.bindDefault(); ok {
+			g.printf("ok || defaults {\nif !ok { raws = []string{%q} }\n", def)
+		} else {
+			g.printf("ok {\n")
+		}
+		if v, ok := f.tags["transform"]; ok && v != "" {
+			var names []string
+			for _, n := range strings.Split(v, ",") {
+				names = append(names, strconv.Quote(n))
+			}
+			g.printf("raws, err := form_builder.TransformValues(raws, %s)\n", strings.Join(names, ", "))
+			g.printf("if err != nil { return nil, err }\n")
+		}
+		// Only strings can always be converted.
+		canFail := f.typ.kind() != "string"
+		if canFail {
+			g.printf("code := \"\"\n")
+		}
+
+		switch {
+		case f.typ.slice:
+			g.printf("items := make([]%s, len(raws))\n", f.typ.elem)
+			g.printf("for i, raw := range raws {\n")
+			g.convert(f.typ, "items[i]")
+			if canFail {
+				g.printf("if code != \"\" { break }\n")
+			}
+			g.printf("}\n")
+			if canFail {
+				g.printf("if code == \"\" { %s = items }\n", f.expr)
+			} else {
+				g.printf("%s = items\n", f.expr)
+			}
+		case f.typ.ptr:
+			g.printf("raw := \"\"\nif len(raws) > 0 { raw = raws[0] }\n")
+			g.printf("if raw == \"\" { %s = nil } else {\n", f.expr)
+			g.printf("if %s == nil { %s = new(%s) }\n", f.expr, f.expr, f.typ.elem)
+			g.convert(f.typ, "*"+f.expr)
+			g.printf("}\n")
+		default:
+			g.printf("raw := \"\"\nif len(raws) > 0 { raw = raws[0] }\n")
+			g.convert(f.typ, f.expr)
+		}
+		if canFail {
+			g.printf("if code != \"\" { %s }\n", strings.TrimSuffix(fieldError(f, "code"), "\n"))
+		}
+		g.printf("}\n")
+	}
+	g.printf("return ferrs, nil\n")
+	g.printf("}\n")
+}
+
+// format returns the expression formatting v, a value of t, as it would be
+// submitted.
+func (g *generator) format(t valueType, v string) string {
+	switch t.kind() {
+	case "string":
+		return "string(" + v + ")"
+	case "bool":
+		g.imports["strconv"] = true
+		return "strconv.FormatBool(bool(" + v + "))"
+	case "int":
+		g.imports["strconv"] = true
+		return "strconv.FormatInt(int64(" + v + "), 10)"
+	case "uint":
+		g.imports["strconv"] = true
+		return "strconv.FormatUint(uint64(" + v + "), 10)"
+	default:
+		g.imports["strconv"] = true
+		return fmt.Sprintf("strconv.FormatFloat(float64(%s), 'g', -1, %d)", v, bits(t.basic))
+	}
+}
+
+func (g *generator) validateMethod(name string, fields []field, hooks []hook) {
+	g.printf("\n// Validate is like form_builder.Validate, without reflection.\n")
+	g.printf("func (s *%s) Validate() form_builder.FieldErrors {\n", name)
+	g.printf("var ferrs form_builder.FieldErrors\n")
+	for _, f := range fields {
+		if len(f.rules) == 0 && f.choices == nil {
+			continue
+		}
+
+		g.printf("{\n")
+		g.value(f)
+		for _, r := range f.rules {
+			switch r.name {
+			case "required":
+				g.printf("if %s { %s }\n", isZero(f), strings.TrimSuffix(fieldError(f, `"required"`), "\n"))
+				continue
+			case "email":
+				g.imports["net/mail"] = true
+				g.printf("if %s {\n", strings.Replace(isZero(f), "==", "!=", 1))
+				g.printf("if addr, err := mail.ParseAddress(string(value)); err != nil || addr.Address != string(value) {\n")
+				g.printf("%s", fieldError(f, `"invalid_email"`))
+				g.printf("}\n}\n")
+				continue
+			}
+
+			var size string
+			codes := map[string]string{"min": "too_small", "max": "too_large"}
+			switch {
+			case f.typ.slice:
+				size = "float64(len(value))"
+				codes = map[string]string{"min": "too_few", "max": "too_many"}
+			case f.typ.kind() == "string":
+				g.imports["unicode/utf8"] = true
+				size = "float64(utf8.RuneCountInString(string(value)))"
+				codes = map[string]string{"min": "too_short", "max": "too_long"}
+			default:
+				size = "float64(value)"
+			}
+			op := "<"
+			if r.name == "max" {
+				op = ">"
+			}
+			bound := strconv.FormatFloat(r.arg, 'g', -1, 64)
+			g.printf("if %s && %s %s %s {\n", strings.Replace(isZero(f), "==", "!=", 1), size, op, bound)
+			g.printf("%s", fieldError(f, strconv.Quote(codes[r.name]), fmt.Sprintf("%q: float64(%s)", r.name, bound)))
+			g.printf("}\n")
+		}
+
+		if f.choices != nil {
+			var values []string
+			for _, c := range f.choices {
+				values = append(values, strconv.Quote(c.Value))
+			}
+			cases := strings.Join(values, ", ")
+			if f.typ.slice {
+				g.printf("for _, item := range value {\n")
+				g.printf("switch %s {\ncase %s:\ncontinue\n}\n", g.format(f.typ, "item"), cases)
+				g.printf("%s", fieldError(f, `"invalid_option"`))
+				g.printf("break\n}\n")
+			} else {
+				g.printf("if %s {\n", strings.Replace(isZero(f), "==", "!=", 1))
+				g.printf("switch %s {\ncase %s:\ndefault:\n", g.format(f.typ, "value"), cases)
+				g.printf("%s", fieldError(f, `"invalid_option"`))
+				g.printf("}\n}\n")
+			}
+		}
+		g.printf("}\n")
+	}
+
+	hasHooks := false
+	for _, h := range hooks {
+		if !h.form && !h.formContext {
+			continue
+		}
+		hasHooks = true
+		if h.form {
+			g.printf("ferrs = append(ferrs, form_builder.ResolveFormErrors(%s.ValidateForm(), %q, formgen%sPaths, formgen%sNames)...)\n", h.expr, h.prefix, name, name)
+		}
+		if h.formContext {
+			g.imports["context"] = true
+			g.printf("ferrs = append(ferrs, form_builder.ResolveFormErrors(%s.ValidateFormContext(context.Background()), %q, formgen%sPaths, formgen%sNames)...)\n", h.expr, h.prefix, name, name)
+		}
+	}
+	g.printf("return ferrs\n")
+	g.printf("}\n")
+
+	if hasHooks {
+		var paths, names []string
+		for _, f := range fields {
+			paths = append(paths, strconv.Quote(f.path))
+			names = append(names, strconv.Quote(f.name))
+		}
+		g.printf("\nvar (\n")
+		g.printf("formgen%sPaths = []string{%s}\n", name, strings.Join(paths, ", "))
+		g.printf("formgen%sNames = []string{%s}\n", name, strings.Join(names, ", "))
+		g.printf(")\n")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_upToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "internal", "conformance")
+	got, err := generate(dir, []string{"Signup", "Search"}, "conformance_formgen.go")
+	if err != nil {
+		t.Fatalf("generate() err = %v; want nil", err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, "conformance_formgen.go"))
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generate() doesn't match conformance_formgen.go; run go generate in internal/conformance")
+	}
+}
+
+func TestGenerate_unsupported(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"Pointer to a nested struct": {
+			src:  "type Inner struct{ A string }\ntype T struct{ In *Inner }",
+			want: "pointers to structs",
+		},
+		"Type from another package": {
+			src:  "import \"time\"\ntype T struct{ At time.Time }",
+			want: "type time.Time is not supported",
+		},
+		"Text unmarshaler": {
+			src:  "type Code string\nfunc (c *Code) UnmarshalText(b []byte) error { return nil }\ntype T struct{ C Code }",
+			want: "TextUnmarshaler",
+		},
+		"Registered validator": {
+			src:  "type T struct{ Email string `form:\"validate=unique_email\"` }",
+			want: "rule \"unique_email\" is not supported",
+		},
+		"Rule that can't apply": {
+			src:  "type T struct{ Agree bool `form:\"validate=min:1\"` }",
+			want: "rule min can't be applied",
+		},
+		"Invalid default": {
+			src:  "type T struct{ Age int `form:\"default=old\"` }",
+			want: "invalid default",
+		},
+		"Invalid option": {
+			src:  "type T struct{ Size int `form:\"options=small\"` }",
+			want: "invalid option",
+		},
+		"Malformed tag": {
+			src:  "type T struct{ Name string `form:\"label\"` }",
+			want: "invalid struct tag",
+		},
+		"Duplicate name": {
+			src:  "type T struct{ Email string `form:\"name=email\"`; Contact string `form:\"name=email\"` }",
+			want: "duplicate input name \"email\" on fields Email and Contact",
+		},
+		"Not a struct": {
+			src:  "type T string",
+			want: "T is not a struct type",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package p\n"+tc.src+"\n"), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = generate(dir, []string{"T"}, "t_formgen.go")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("generate() err = %v; want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
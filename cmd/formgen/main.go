@@ -0,0 +1,67 @@
+// Command formgen generates methods that render, bind and validate form
+// structs without reflection, for handlers hot enough that reflecting over
+// a struct on every request matters.
+//
+// It is meant to be run by go generate, from the package declaring the
+// structs:
+//
+//	//go:generate go run form_builder/cmd/formgen -type=Signup,Login
+//
+// For each type T it generates:
+//
+//	func (s *T) Fields(opts ...form_builder.Option) ([]form_builder.Field, error)
+//	func (s *T) Bind(values url.Values, opts ...form_builder.Option) (form_builder.FieldErrors, error)
+//	func (s *T) Validate() form_builder.FieldErrors
+//
+// which return exactly what form_builder.Fields, Bind and Validate return
+// for the same struct. Only what can be generated exactly is supported:
+// fields of basic types, named types based on them, pointers and slices of
+// these, and nested structs declared in the same package. Structs using
+// anything else, such as file uploads, types with their own text encoding,
+// pointers to nested structs or registered validators, are reported as
+// errors and should keep using the reflection-based functions.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of type names; must be set")
+	output := flag.String("output", "", "output file name; default <type>_formgen.go, after the first type")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: formgen -type=T[,T...] [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	name := *output
+	if name == "" {
+		name = strings.ToLower(types[0]) + "_formgen.go"
+	}
+	name = filepath.Join(dir, name)
+
+	src, err := generate(dir, types, filepath.Base(name))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = os.WriteFile(name, src, 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
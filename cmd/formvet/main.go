@@ -0,0 +1,18 @@
+// Command formvet checks the form struct tags of Go packages. It is meant
+// to be run by go vet:
+//
+//	go install form_builder/cmd/formvet
+//	go vet -vettool=$(which formvet) ./...
+//
+// See package form_builder/formvet for what it reports.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"form_builder/formvet"
+)
+
+func main() {
+	unitchecker.Main(formvet.Analyzer)
+}
@@ -0,0 +1,184 @@
+package form_builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// Compiled is a form built from the struct type T whose tags have all been
+// checked by Compile, so rendering, binding and validating it can't fail
+// because of a mistake in them.
+type Compiled[T any] struct {
+	opts []Option
+}
+
+// Compile checks every tag of T, which must be a struct type, and returns a
+// Compiled form for it. Anything that would otherwise only fail when a form
+// is first rendered, bound or validated is reported, such as a malformed
+// tag, an unknown rule or transform, a rule that can't apply to its field,
+// a default or option that can't be converted to its field's type, two
+// fields with the same input name, unless DisambiguateNames is given, or a
+// field of a type that can't be bound. It is meant to be called once at
+// startup:
+//
+//	var signupForm = form_builder.MustCompile[Signup]()
+//
+// Rules naming registered validators are only known once the validators are
+// registered, so a form using them must be compiled afterwards. Package
+// level variables are initialized before the init functions of their
+// package run, so compile such a form in the init function that registers
+// its validators instead:
+//
+//	var signupForm *form_builder.Compiled[Signup]
+//
+//	func init() {
+//		form_builder.RegisterValidator("unique_email", uniqueEmail)
+//		signupForm = form_builder.MustCompile[Signup]()
+//	}
+//
+// The options are used every time the form is rendered, bound or
+// validated. Options for a single request can be added with Render.
+func Compile[T any](opts ...Option) (*Compiled[T], error) {
+	cfg := newConfig(opts)
+
+	refVal := reflect.ValueOf(new(T)).Elem()
+	if refVal.Kind() != reflect.Struct {
+		return nil, errNotStruct
+	}
+	walkLeaves := func(alloc bool, fn func(leaf) error) error {
+		return walk(refVal, alloc, nil, fn)
+	}
+	err := walkLeaves(false, uniqueNames(cfg, walkLeaves, func(l leaf) error {
+		return checkLeaf(l, cfg)
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &Compiled[T]{opts: opts}, nil
+}
+
+// MustCompile is like Compile but panics if T can't be compiled.
+func MustCompile[T any](opts ...Option) *Compiled[T] {
+	c, err := Compile[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Render writes the complete form for v to w. A nil v renders an empty
+// form. The options are added to those given to Compile for this render
+// only, for anything that depends on the request, such as WithErrors,
+// WithInput, WithCSRF or WithTranslator:
+//
+//	v, errors := signupForm.Bind(r)
+//	...
+//	err := signupForm.Render(w, v,
+//		form_builder.WithErrors(errors...),
+//		form_builder.WithInput(r.PostForm),
+//		form_builder.WithCSRF(csrf, r))
+func (c *Compiled[T]) Render(w io.Writer, v *T, opts ...Option) error {
+	if v == nil {
+		v = new(T)
+	}
+	form, err := NewForm(v, append(c.options(), opts...)...)
+	if err != nil {
+		return err
+	}
+	html, err := form.Render()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, string(html))
+	return err
+}
+
+// Bind returns a new T bound from the values and files submitted with r,
+// with fields that weren't submitted set from their default tags, other
+// than checkboxes, along with any errors converting them. A request that
+// can't be parsed is reported as a form-level error with the Code
+// "bad_request".
+func (c *Compiled[T]) Bind(r *http.Request) (*T, FieldErrors) {
+	v := new(T)
+	ferrs, err := BindRequest(r, v, append(c.options(), WithDefaults())...)
+	if err != nil {
+		return v, FieldErrors{{Code: "bad_request"}}
+	}
+	return v, ferrs
+}
+
+// Validate returns an error for each rule v breaks, along with any error
+// checking them, as Validate does.
+func (c *Compiled[T]) Validate(v *T) (FieldErrors, error) {
+	return c.ValidateContext(context.Background(), v)
+}
+
+// ValidateContext is like Validate, but passes ctx on to registered
+// validators, as ValidateContext does.
+func (c *Compiled[T]) ValidateContext(ctx context.Context, v *T) (FieldErrors, error) {
+	return ValidateContext(ctx, v, c.options()...)
+}
+
+func (c *Compiled[T]) options() []Option {
+	return c.opts[:len(c.opts):len(c.opts)]
+}
+
+// checkLeaf reports the first problem with l's tags or type that would stop
+// it from being rendered, bound or validated.
+func checkLeaf(l leaf, cfg *config) error {
+	_, err := fieldOf(l, cfg)
+	if err != nil {
+		return err
+	}
+
+	rules, err := parseRules(l.tags["validate"])
+	if err != nil {
+		return fmt.Errorf("%v on field %s", err, l.field.Name)
+	}
+	zero := valueOf(reflect.New(l.value.Type()).Elem())
+	for _, r := range rules {
+		if check, ok := builtinRules[r.name]; ok {
+			_, _, err := check(zero, r.arg)
+			if err != nil {
+				return fmt.Errorf("form: invalid rule %q on field %s: %v", r.name, l.field.Name, err)
+			}
+		}
+	}
+
+	_, err = parseTransforms(l.tags["transform"])
+	if err != nil {
+		return fmt.Errorf("%v on field %s", err, l.field.Name)
+	}
+
+	choices, _ := choicesOf(l)
+	for _, c := range choices {
+		_, err := convert(elemType(l.value.Type()), c.Value)
+		if err != nil {
+			return fmt.Errorf("form: invalid option %q on field %s: %v", c.Value, l.field.Name, err)
+		}
+	}
+
+	if isFile(l.value.Type()) {
+		if v, ok := l.tags["maxsize"]; ok {
+			_, err := parseSize(v)
+			if err != nil {
+				return fmt.Errorf("form: invalid maxsize %q on field %s", v, l.field.Name)
+			}
+		}
+		return nil
+	}
+
+	// Binding an empty value fails with a convertError for fields that
+	// can't be empty, and any other error for fields that can't be bound
+	// at all.
+	var cerr *convertError
+	err = setValues(reflect.New(l.value.Type()).Elem(), nil)
+	if err != nil && !errors.As(err, &cerr) {
+		return fmt.Errorf("%v on field %s", err, l.field.Name)
+	}
+	return nil
+}
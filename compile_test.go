@@ -0,0 +1,235 @@
+package form_builder_test
+
+import (
+	"context"
+	"errors"
+	"form_builder"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type newsletter struct {
+	Email  string `form:"type=email;validate=required,email"`
+	Topics string `form:"options=go,web;default=go"`
+}
+
+func TestCompile(t *testing.T) {
+	form := form_builder.MustCompile[newsletter](form_builder.WithIDPrefix("news"))
+
+	var sb strings.Builder
+	err := form.Render(&sb, nil, form_builder.WithErrors(form_builder.FieldError{Field: "Email", Message: "Email is taken"}))
+	if err != nil {
+		t.Fatalf("Render() err = %v; want nil", err)
+	}
+	for _, want := range []string{`id="news-Email"`, "Email is taken", `<option value="go" selected>`} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("Render() = %s; want it to contain %s", sb.String(), want)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"Email": {"nope"}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	v, errors := form.Bind(r)
+	if len(errors) > 0 {
+		t.Fatalf("Bind() errors = %v; want none", errors)
+	}
+	if *v != (newsletter{Email: "nope", Topics: "go"}) {
+		t.Errorf("Bind() = %+v; want the submitted email and default topic", *v)
+	}
+
+	want := form_builder.FieldErrors{
+		{Field: "Email", Code: "invalid_email", Params: map[string]interface{}{"label": "Email"}},
+	}
+	got, err := form.Validate(v)
+	if err != nil {
+		t.Fatalf("Validate() err = %v; want nil", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v; want %v", got, want)
+	}
+}
+
+func TestCompile_validatorFailure(t *testing.T) {
+	type lookup struct {
+		Email string `form:"validate=test_timeout"`
+	}
+	form := form_builder.MustCompile[lookup]()
+
+	_, err := form.Validate(&lookup{Email: "a@cc.cc"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Validate() err = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCompile_renderOptions(t *testing.T) {
+	type signup struct {
+		Age int `form:"validate=min:18"`
+	}
+	form := form_builder.MustCompile[signup](form_builder.WithIDPrefix("signup"))
+	csrf := &form_builder.CSRF{
+		Secret:    []byte("0123456789abcdef0123456789abcdef"),
+		SessionID: func(r *http.Request) string { return "alice" },
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"Age": {"abc"}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	v, errors := form.Bind(r)
+	if len(errors) != 1 {
+		t.Fatalf("Bind() errors = %v; want one", errors)
+	}
+
+	var sb strings.Builder
+	err := form.Render(&sb, v,
+		form_builder.WithErrors(errors...),
+		form_builder.WithInput(r.PostForm),
+		form_builder.WithCSRF(csrf, r))
+	if err != nil {
+		t.Fatalf("Render() err = %v; want nil", err)
+	}
+	for _, want := range []string{`id="signup-Age"`, `value="abc"`, `name="csrf_token"`, "Age must be a whole number"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("Render() = %s; want it to contain %s", sb.String(), want)
+		}
+	}
+}
+
+func TestCompile_badRequest(t *testing.T) {
+	form := form_builder.MustCompile[newsletter]()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("--x\r\nbroken"))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	_, errors := form.Bind(r)
+	if len(errors) != 1 || errors[0].Code != "bad_request" {
+		t.Errorf("Bind() errors = %v; want a bad_request error", errors)
+	}
+}
+
+func TestCompile_disambiguateNames(t *testing.T) {
+	type contact struct {
+		Email string `form:"name=email;validate=email"`
+	}
+	type order struct {
+		Email    string `form:"name=email"`
+		Shipping contact
+	}
+
+	_, err := form_builder.Compile[order]()
+	if err == nil || !strings.Contains(err.Error(), "Email and Shipping.Email") {
+		t.Errorf("Compile() err = %v; want it to name both fields", err)
+	}
+
+	form := form_builder.MustCompile[order](form_builder.DisambiguateNames())
+	var sb strings.Builder
+	err = form.Render(&sb, nil)
+	if err != nil {
+		t.Fatalf("Render() err = %v; want nil", err)
+	}
+	if !strings.Contains(sb.String(), `name="Shipping.Email"`) {
+		t.Errorf("Render() = %s; want an input named Shipping.Email", sb.String())
+	}
+
+	values := url.Values{"email": {"a@example.com"}, "Shipping.Email": {"nope"}}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	v, errors := form.Bind(r)
+	if len(errors) > 0 {
+		t.Fatalf("Bind() errors = %v; want none", errors)
+	}
+	if want := (order{Email: "a@example.com", Shipping: contact{Email: "nope"}}); *v != want {
+		t.Errorf("Bind() = %+v; want %+v", *v, want)
+	}
+
+	want := form_builder.FieldErrors{
+		{Field: "Shipping.Email", Code: "invalid_email", Params: map[string]interface{}{"label": "Email"}},
+	}
+	got, err := form.Validate(v)
+	if err != nil {
+		t.Fatalf("Validate() err = %v; want nil", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v; want %v", got, want)
+	}
+}
+
+func TestCompile_errors(t *testing.T) {
+	tests := map[string]func() error{
+		"Not a struct": func() error {
+			_, err := form_builder.Compile[string]()
+			return err
+		},
+		"Malformed tag": func() error {
+			_, err := form_builder.Compile[struct {
+				Name string `form:"label"`
+			}]()
+			return err
+		},
+		"Unknown rule": func() error {
+			_, err := form_builder.Compile[struct {
+				Name string `form:"validate=shiny"`
+			}]()
+			return err
+		},
+		"Rule that can't apply": func() error {
+			_, err := form_builder.Compile[struct {
+				Agree bool `form:"validate=min:1"`
+			}]()
+			return err
+		},
+		"Invalid rule argument": func() error {
+			_, err := form_builder.Compile[struct {
+				Name string `form:"validate=max:lots"`
+			}]()
+			return err
+		},
+		"Unknown transform": func() error {
+			_, err := form_builder.Compile[struct {
+				Name string `form:"transform=shout"`
+			}]()
+			return err
+		},
+		"Invalid default": func() error {
+			_, err := form_builder.Compile[struct {
+				Age int `form:"default=old"`
+			}]()
+			return err
+		},
+		"Invalid option": func() error {
+			_, err := form_builder.Compile[struct {
+				Size int `form:"options=small,large"`
+			}]()
+			return err
+		},
+		"Invalid maxsize": func() error {
+			_, err := form_builder.Compile[struct {
+				Avatar *multipart.FileHeader `form:"maxsize=huge"`
+			}]()
+			return err
+		},
+		"Duplicate name": func() error {
+			_, err := form_builder.Compile[struct {
+				Email   string
+				Contact string `form:"name=Email"`
+			}]()
+			return err
+		},
+		"Unsupported type": func() error {
+			_, err := form_builder.Compile[struct {
+				Meta map[string]string
+			}]()
+			return err
+		},
+	}
+
+	for name, compile := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := compile(); err == nil {
+				t.Errorf("Compile() err = nil; want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package form_builder
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidCSRFToken is returned by CSRF.Verify when a request has no
+// token, or one that is forged, expired or from another session.
+var ErrInvalidCSRFToken = errors.New("form: invalid CSRF token")
+
+var (
+	errCSRFConfig = errors.New("form: CSRF needs a Secret and a SessionID")
+	errNoSession  = errors.New("form: can't issue a CSRF token without a session")
+)
+
+// csrfMessage is the form-level error shown when a submission fails CSRF
+// verification. In practice this is almost always a form that was left
+// open until its token expired.
+const csrfMessage = "This form has expired. Please submit it again."
+
+const (
+	csrfNonceLen = 16
+	csrfTimeLen  = 8
+)
+
+// CSRF issues and verifies tokens that protect forms against cross-site
+// request forgery. Tokens are signed with HMAC-SHA256 and bound to the
+// user's session, so a token issued to one session is rejected in any
+// other.
+//
+// Pass a CSRF to WithCSRF to have a Form render its token as a hidden
+// input, and check submissions with Verify or Protect, or by setting
+// Handler.CSRF.
+type CSRF struct {
+	// Secret is the key tokens are signed with. It must be set and kept
+	// private, and should be at least 32 random bytes.
+	Secret []byte
+	// SessionID returns the ID of the session r belongs to. It must be
+	// set. No token is issued or accepted for a request it returns "" for,
+	// since that token would be valid for every client without a session,
+	// so anonymous visitors need a session of their own, such as one kept
+	// in a random cookie, to submit a protected form.
+	SessionID func(r *http.Request) string
+	// FieldName is the name of the hidden input holding the token. It
+	// defaults to "csrf_token".
+	FieldName string
+	// MaxAge is how long a token is valid for. It defaults to 12 hours.
+	MaxAge time.Duration
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// Token returns a new token for the session r belongs to. It returns an
+// error if c has no Secret or SessionID, or if r has no session.
+func (c *CSRF) Token(r *http.Request) (string, error) {
+	session, err := c.sessionID(r)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, csrfNonceLen+csrfTimeLen, csrfNonceLen+csrfTimeLen+sha256.Size)
+	_, err = rand.Read(buf[:csrfNonceLen])
+	if err != nil {
+		return "", err
+	}
+	binary.BigEndian.PutUint64(buf[csrfNonceLen:], uint64(c.clock().Unix()))
+
+	buf = append(buf, c.sign(session, buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Verify checks the token submitted with r, either in the form field named
+// FieldName or in the X-CSRF-Token header. It returns ErrInvalidCSRFToken
+// if the token is missing or invalid, or if r has no session, and another
+// error if c has no Secret or SessionID.
+func (c *CSRF) Verify(r *http.Request) error {
+	session, err := c.sessionID(r)
+	if err == errNoSession {
+		return ErrInvalidCSRFToken
+	}
+	if err != nil {
+		return err
+	}
+
+	token := r.Header.Get("X-CSRF-Token")
+	if token == "" {
+		token = r.PostFormValue(c.fieldName())
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != csrfNonceLen+csrfTimeLen+sha256.Size {
+		return ErrInvalidCSRFToken
+	}
+
+	payload, mac := buf[:csrfNonceLen+csrfTimeLen], buf[csrfNonceLen+csrfTimeLen:]
+	if !hmac.Equal(mac, c.sign(session, payload)) {
+		return ErrInvalidCSRFToken
+	}
+
+	issued := time.Unix(int64(binary.BigEndian.Uint64(payload[csrfNonceLen:])), 0)
+	if c.clock().Sub(issued) > c.maxAge() {
+		return ErrInvalidCSRFToken
+	}
+	return nil
+}
+
+// Protect is middleware that responds with 403 Forbidden to any request
+// that isn't GET, HEAD, OPTIONS or TRACE and fails Verify, or with 500
+// Internal Server Error if c has no Secret or SessionID.
+func (c *CSRF) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		default:
+			err := c.Verify(r)
+			if err == ErrInvalidCSRFToken {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sessionID returns the ID of the session r belongs to, which tokens are
+// bound to.
+func (c *CSRF) sessionID(r *http.Request) (string, error) {
+	if len(c.Secret) == 0 || c.SessionID == nil {
+		return "", errCSRFConfig
+	}
+	session := c.SessionID(r)
+	if session == "" {
+		return "", errNoSession
+	}
+	return session, nil
+}
+
+func (c *CSRF) sign(session string, payload []byte) []byte {
+	h := hmac.New(sha256.New, c.Secret)
+	h.Write([]byte(session))
+	// Separate the session ID from the payload so the two can't be shifted
+	// into each other.
+	h.Write([]byte{0})
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+func (c *CSRF) fieldName() string {
+	if c.FieldName == "" {
+		return "csrf_token"
+	}
+	return c.FieldName
+}
+
+func (c *CSRF) maxAge() time.Duration {
+	if c.MaxAge == 0 {
+		return 12 * time.Hour
+	}
+	return c.MaxAge
+}
+
+func (c *CSRF) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
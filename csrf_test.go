@@ -0,0 +1,271 @@
+package form_builder
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCSRFRequest(method, session string, form url.Values) *http.Request {
+	r := httptest.NewRequest(method, "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Session", session)
+	return r
+}
+
+func TestCSRF_Verify(t *testing.T) {
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	c := &CSRF{
+		Secret:    []byte("0123456789abcdef0123456789abcdef"),
+		SessionID: func(r *http.Request) string { return r.Header.Get("X-Session") },
+		MaxAge:    time.Hour,
+		now:       func() time.Time { return now },
+	}
+
+	token, err := c.Token(newCSRFRequest(http.MethodGet, "alice", nil))
+	if err != nil {
+		t.Fatalf("Token() err = %v; want nil", err)
+	}
+
+	tampered := "A" + token[1:]
+	if token[0] == 'A' {
+		tampered = "B" + token[1:]
+	}
+
+	tests := map[string]struct {
+		r       *http.Request
+		elapsed time.Duration
+		wantErr error
+	}{
+		"Valid token": {
+			r: newCSRFRequest(http.MethodPost, "alice", url.Values{"csrf_token": {token}}),
+		},
+		"Valid token in a header": {
+			r: func() *http.Request {
+				r := newCSRFRequest(http.MethodPost, "alice", nil)
+				r.Header.Set("X-CSRF-Token", token)
+				return r
+			}(),
+		},
+		"Missing token": {
+			r:       newCSRFRequest(http.MethodPost, "alice", nil),
+			wantErr: ErrInvalidCSRFToken,
+		},
+		"No session": {
+			r:       newCSRFRequest(http.MethodPost, "", url.Values{"csrf_token": {token}}),
+			wantErr: ErrInvalidCSRFToken,
+		},
+		"Another session": {
+			r:       newCSRFRequest(http.MethodPost, "mallory", url.Values{"csrf_token": {token}}),
+			wantErr: ErrInvalidCSRFToken,
+		},
+		"Tampered token": {
+			r:       newCSRFRequest(http.MethodPost, "alice", url.Values{"csrf_token": {tampered}}),
+			wantErr: ErrInvalidCSRFToken,
+		},
+		"Malformed token": {
+			r:       newCSRFRequest(http.MethodPost, "alice", url.Values{"csrf_token": {"!!"}}),
+			wantErr: ErrInvalidCSRFToken,
+		},
+		"Expired token": {
+			r:       newCSRFRequest(http.MethodPost, "alice", url.Values{"csrf_token": {token}}),
+			elapsed: time.Hour + time.Second,
+			wantErr: ErrInvalidCSRFToken,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c.now = func() time.Time { return now.Add(tc.elapsed) }
+			if err := c.Verify(tc.r); err != tc.wantErr {
+				t.Errorf("Verify() err = %v; want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSRF_misconfigured(t *testing.T) {
+	session := func(r *http.Request) string { return r.Header.Get("X-Session") }
+	tests := map[string]struct {
+		c       *CSRF
+		session string
+		wantErr error
+	}{
+		"No secret": {
+			c:       &CSRF{SessionID: session},
+			session: "alice",
+			wantErr: errCSRFConfig,
+		},
+		"No session ID": {
+			c:       &CSRF{Secret: []byte("0123456789abcdef0123456789abcdef")},
+			session: "alice",
+			wantErr: errCSRFConfig,
+		},
+		"Empty session": {
+			c:       &CSRF{Secret: []byte("0123456789abcdef0123456789abcdef"), SessionID: session},
+			wantErr: errNoSession,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.c.Token(newCSRFRequest(http.MethodGet, tc.session, nil))
+			if err != tc.wantErr {
+				t.Errorf("Token() err = %v; want %v", err, tc.wantErr)
+			}
+			if err := tc.c.Verify(newCSRFRequest(http.MethodPost, tc.session, nil)); err == nil {
+				t.Errorf("Verify() err = nil; want an error")
+			}
+		})
+	}
+}
+
+func TestCSRF_Protect(t *testing.T) {
+	c := &CSRF{
+		Secret:    []byte("0123456789abcdef0123456789abcdef"),
+		SessionID: func(r *http.Request) string { return r.Header.Get("X-Session") },
+	}
+	h := c.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	token, err := c.Token(newCSRFRequest(http.MethodGet, "alice", nil))
+	if err != nil {
+		t.Fatalf("Token() err = %v; want nil", err)
+	}
+
+	tests := map[string]struct {
+		h          http.Handler
+		r          *http.Request
+		wantStatus int
+	}{
+		"Safe methods are not checked": {
+			r:          newCSRFRequest(http.MethodGet, "alice", nil),
+			wantStatus: http.StatusOK,
+		},
+		"Valid token": {
+			r:          newCSRFRequest(http.MethodPost, "alice", url.Values{"csrf_token": {token}}),
+			wantStatus: http.StatusOK,
+		},
+		"Invalid token": {
+			r:          newCSRFRequest(http.MethodPost, "mallory", url.Values{"csrf_token": {token}}),
+			wantStatus: http.StatusForbidden,
+		},
+		"Misconfigured": {
+			h:          (&CSRF{}).Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+			r:          newCSRFRequest(http.MethodPost, "alice", url.Values{"csrf_token": {token}}),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := h
+			if tc.h != nil {
+				h = tc.h
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tc.r)
+			if w.Code != tc.wantStatus {
+				t.Errorf("status = %d; want %d", w.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_CSRF(t *testing.T) {
+	c := &CSRF{
+		Secret:    []byte("0123456789abcdef0123456789abcdef"),
+		SessionID: func(r *http.Request) string { return r.Header.Get("X-Session") },
+		FieldName: "token",
+	}
+	page := template.Must(template.New("page").Parse(`{{.Render}}`))
+	h := NewHandler(page, nil, func(ctx context.Context, v *struct{ Name string }) (string, []FieldError) {
+		return "/done", nil
+	})
+	h.CSRF = c
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newCSRFRequest(http.MethodGet, "alice", nil))
+	if !strings.Contains(w.Body.String(), `<input type="hidden" name="token" value="`) {
+		t.Fatalf("GET body = %q; want a hidden token input", w.Body.String())
+	}
+	token, err := c.Token(newCSRFRequest(http.MethodGet, "alice", nil))
+	if err != nil {
+		t.Fatalf("Token() err = %v; want nil", err)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newCSRFRequest(http.MethodPost, "mallory", url.Values{"Name": {"Mallory"}, "token": {token}}))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("forged POST status = %d; want %d", w.Code, http.StatusForbidden)
+	}
+	for _, want := range []string{csrfMessage, `value="Mallory"`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("forged POST body = %q; want it to contain %q", w.Body.String(), want)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newCSRFRequest(http.MethodPost, "alice", url.Values{"Name": {"Alice"}, "token": {token}}))
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("valid POST status = %d; want %d", w.Code, http.StatusSeeOther)
+	}
+}
+
+// csrfBound is set whenever a value is bound with the test_csrf_spy
+// transform.
+var csrfBound bool
+
+func init() {
+	RegisterTransform("test_csrf_spy", func(s string) string {
+		csrfBound = true
+		return s
+	})
+}
+
+func TestHandler_CSRFBeforeBind(t *testing.T) {
+	page := template.Must(template.New("page").Parse(`{{.Render}}`))
+	onValid := func(ctx context.Context, v *struct {
+		Name string `form:"transform=test_csrf_spy"`
+	}) (string, []FieldError) {
+		return "/done", nil
+	}
+
+	tests := map[string]struct {
+		csrf       *CSRF
+		wantStatus int
+	}{
+		"Forged": {
+			csrf: &CSRF{
+				Secret:    []byte("0123456789abcdef0123456789abcdef"),
+				SessionID: func(r *http.Request) string { return r.Header.Get("X-Session") },
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		"Misconfigured": {
+			csrf:       &CSRF{},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			csrfBound = false
+			h := NewHandler(page, nil, onValid)
+			h.CSRF = tc.csrf
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newCSRFRequest(http.MethodPost, "mallory", url.Values{"Name": {"Mallory"}, "csrf_token": {"forged"}}))
+			if w.Code != tc.wantStatus {
+				t.Errorf("status = %d; want %d", w.Code, tc.wantStatus)
+			}
+			if csrfBound {
+				t.Errorf("ServeHTTP() bound the submission before verifying its CSRF token")
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package form_builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Definition describes a form built at runtime rather than from a Go
+// struct, such as a survey created by an admin. It can be passed anywhere a
+// struct can, including Fields, HTML, NewForm, Validate and JSONSchema, and
+// renders exactly like the equivalent struct. Use Data to bind and validate
+// its values.
+type Definition struct {
+	Fields []FieldDefinition `json:"fields"`
+}
+
+// FieldDefinition describes a single input of a Definition, or a group of
+// inputs if Fields is set. Each property has the same meaning as the
+// struct tag of the same name.
+type FieldDefinition struct {
+	// Name is the input name. The names of the inputs in a group are
+	// prefixed with the group's name, just as the fields of nested structs
+	// are.
+	Name string `json:"name"`
+	// Type is the input type. It also decides the type of the field's
+	// value: a float64 for number and range, a bool for checkbox, a
+	// *multipart.FileHeader for file and a string for anything else.
+	Type        string   `json:"type,omitempty"`
+	Label       string   `json:"label,omitempty"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Help        string   `json:"help,omitempty"`
+	Options     []Choice `json:"options,omitempty"`
+	// Multiple makes the field's value a slice, for inputs that take more
+	// than one value, such as a multiple select.
+	Multiple  bool     `json:"multiple,omitempty"`
+	Validate  []string `json:"validate,omitempty"`
+	Transform []string `json:"transform,omitempty"`
+	// Default is a single value, even for a Multiple field, where it
+	// becomes the only element.
+	Default interface{} `json:"default,omitempty"`
+	Accept  string      `json:"accept,omitempty"`
+	MaxSize string      `json:"maxsize,omitempty"`
+
+	Fields []FieldDefinition `json:"fields,omitempty"`
+
+	// Struct, if set, is a struct or a pointer to one whose fields are added
+	// in place of this one, named as if the struct were nested in the
+	// enclosing group. Its values are read from and bound to the struct
+	// itself, so it must be a pointer to be bound, and Validate calls its
+	// FormValidator methods. Every other property is ignored.
+	Struct interface{} `json:"-"`
+}
+
+// valueTypes maps input types to the type of their values, for those that
+// aren't strings.
+var valueTypes = map[string]reflect.Type{
+	"number":   reflect.TypeOf(float64(0)),
+	"range":    reflect.TypeOf(float64(0)),
+	"checkbox": reflect.TypeOf(false),
+	"file":     fileHeaderType,
+}
+
+// ParseDefinition parses a Definition from JSON such as:
+//
+//	{"fields": [
+//		{"name": "email", "type": "email", "label": "Email", "validate": ["required", "email"]},
+//		{"name": "plan", "options": [{"value": "free", "label": "Free"}, {"value": "pro", "label": "Pro"}]},
+//		{"name": "address", "fields": [{"name": "street"}, {"name": "city"}]}
+//	]}
+//
+// It returns an error if the definition is malformed, so mistakes are
+// found when a form is saved rather than when it is first used.
+func ParseDefinition(data []byte) (*Definition, error) {
+	var d Definition
+	err := json.Unmarshal(data, &d)
+	if err != nil {
+		return nil, fmt.Errorf("form: invalid definition: %v", err)
+	}
+	err = d.check()
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// check reports the first problem with d that would stop it from being
+// rendered, bound or validated.
+func (d *Definition) check() error {
+	cfg := newConfig(nil)
+	return walkForm(d, false, cfg, func(l leaf) error {
+		return checkLeaf(l, cfg)
+	})
+}
+
+// Data returns the form described by d, holding the values in values.
+// Values are keyed by field name, with the values of a group in a nested
+// map, and may be nil to render an empty form.
+func (d *Definition) Data(values map[string]interface{}) *Data {
+	return &Data{def: d, values: values}
+}
+
+func (d *Definition) walkLeaves(alloc bool, fn func(leaf) error) error {
+	return d.Data(nil).walkLeaves(alloc, fn)
+}
+
+// Data is a form built from a Definition along with its values. It can be
+// passed anywhere a struct can, and as the destination of Bind, which sets
+// every field's value in the map given to Definition.Data.
+type Data struct {
+	def    *Definition
+	values map[string]interface{}
+}
+
+// Values returns the values of the form.
+func (d *Data) Values() map[string]interface{} {
+	return d.values
+}
+
+func (d *Data) walkLeaves(alloc bool, fn func(leaf) error) error {
+	if alloc && d.values == nil {
+		return fmt.Errorf("form: can't bind to a definition without a values map")
+	}
+	return walkDefinitions(d.def.Fields, d.values, alloc, nil, fn)
+}
+
+// walkDefinitions is walk for field definitions: it calls fn for every
+// input in defs, reading their values from values. If alloc is true, each
+// value is stored back in values once fn returns, and groups missing from
+// values are added.
+func walkDefinitions(defs []FieldDefinition, values map[string]interface{}, alloc bool, parentNames []string, fn func(leaf) error) error {
+	seen := make(map[string]bool, len(defs))
+	for _, fd := range defs {
+		if fd.Struct != nil {
+			refVal := valueOf(fd.Struct)
+			if refVal.Kind() != reflect.Struct {
+				return errNotStruct
+			}
+			if alloc && !refVal.CanAddr() {
+				return errNotStructPointer
+			}
+			err := walk(refVal, alloc, parentNames, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fd.Name == "" || strings.Contains(fd.Name, ".") {
+			return fmt.Errorf("form: invalid field name %q", fd.Name)
+		}
+		if seen[fd.Name] {
+			return fmt.Errorf("form: duplicate field name %q", fd.Name)
+		}
+		seen[fd.Name] = true
+
+		names := append(parentNames[:len(parentNames):len(parentNames)], fd.Name)
+
+		if fd.Fields != nil {
+			group, _ := values[fd.Name].(map[string]interface{})
+			if alloc && group == nil {
+				group = make(map[string]interface{})
+				values[fd.Name] = group
+			}
+			err := walkDefinitions(fd.Fields, group, alloc, names, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := strings.Join(names, ".")
+		t, ok := valueTypes[fd.Type]
+		if !ok {
+			t = reflect.TypeOf("")
+		}
+		if fd.Multiple {
+			t = reflect.SliceOf(t)
+		}
+
+		if _, ok := fd.Default.([]interface{}); ok {
+			return fmt.Errorf("form: default of field %s must be a single value, not a list", name)
+		}
+
+		v := reflect.New(t).Elem()
+		if raw, ok := values[fd.Name]; ok {
+			err := setDefined(v, raw)
+			if err != nil {
+				return fmt.Errorf("%v for field %s", err, name)
+			}
+		}
+
+		invalid := false
+		err := fn(leaf{
+			field:   reflect.StructField{Name: fd.Name, Type: t},
+			value:   v,
+			path:    name,
+			name:    name,
+			tags:    fd.tags(),
+			choices: fd.choices(),
+			invalid: func() { invalid = true },
+		})
+		if err != nil {
+			return err
+		}
+
+		// Values that couldn't be bound are left as they were, just like
+		// the fields of a struct.
+		if alloc && !invalid && !(isFile(t) && v.IsZero()) {
+			values[fd.Name] = v.Interface()
+		}
+	}
+	return nil
+}
+
+// walkStructs calls fn for the Struct of every field definition in defs
+// that has one, along with the names of the groups it is in.
+func walkStructs(defs []FieldDefinition, parentNames []string, fn func(v reflect.Value, names []string)) {
+	for _, fd := range defs {
+		switch {
+		case fd.Struct != nil:
+			fn(valueOf(fd.Struct), parentNames)
+		case fd.Fields != nil:
+			walkStructs(fd.Fields, append(parentNames[:len(parentNames):len(parentNames)], fd.Name), fn)
+		}
+	}
+}
+
+// tags returns the struct tags equivalent to fd.
+func (fd FieldDefinition) tags() map[string]string {
+	tags := make(map[string]string)
+	set := func(k, v string) {
+		if v != "" {
+			tags[k] = v
+		}
+	}
+	set("type", fd.Type)
+	set("label", fd.Label)
+	set("placeholder", fd.Placeholder)
+	set("help", fd.Help)
+	set("validate", strings.Join(fd.Validate, ","))
+	set("transform", strings.Join(fd.Transform, ","))
+	set("accept", fd.Accept)
+	set("maxsize", fd.MaxSize)
+	if fd.Default != nil {
+		set("default", fmt.Sprint(fd.Default))
+	}
+	return tags
+}
+
+// choices returns the options of fd with their labels defaulting to their
+// values, as they do in an options tag.
+func (fd FieldDefinition) choices() []Choice {
+	var choices []Choice
+	for _, c := range fd.Options {
+		if c.Label == "" {
+			c.Label = c.Value
+		}
+		choices = append(choices, c)
+	}
+	return choices
+}
+
+// setDefined sets v from raw, a value from the map given to
+// Definition.Data. raw may already have v's type, or be the result of
+// decoding JSON, such as a float64 for an int or a []interface{} for a
+// slice.
+func setDefined(v reflect.Value, raw interface{}) error {
+	if raw == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(raw)
+	switch {
+	case rv.Type().AssignableTo(v.Type()):
+		v.Set(rv)
+	case v.Kind() == reflect.Slice && rv.Kind() == reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			err := setDefined(s.Index(i), rv.Index(i).Interface())
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case rv.Kind() == reflect.String:
+		return setValue(v, rv.String())
+	case v.Kind() == reflect.String:
+		s, err := formatValue(rv)
+		if err != nil {
+			return fmt.Errorf("form: can't use %T as a value", raw)
+		}
+		v.SetString(s)
+	case rv.Type().ConvertibleTo(v.Type()):
+		v.Set(rv.Convert(v.Type()))
+	default:
+		return fmt.Errorf("form: can't use %T as a value", raw)
+	}
+	return nil
+}
+
+// elemType returns the type of the values of a field of type t: the
+// element type of slices, and t itself otherwise.
+func elemType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr && !isFile(t) {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && t != fileHeadersType {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,176 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+const surveyJSON = `{"fields": [
+	{"name": "Email", "type": "email", "label": "Email address", "validate": ["required", "email"], "transform": ["trim"]},
+	{"name": "Plan", "options": [{"value": "free", "label": "Free"}, {"value": "pro", "label": "Pro"}], "default": "free"},
+	{"name": "Topics", "options": [{"value": "go"}, {"value": "web"}], "multiple": true},
+	{"name": "Rating", "type": "number", "help": "From 1 to 5", "validate": ["min:1", "max:5"]},
+	{"name": "Address", "fields": [
+		{"name": "Street", "placeholder": "123 Main St"},
+		{"name": "City"}
+	]}
+]}`
+
+// survey is the struct equivalent to surveyJSON.
+type survey struct {
+	Email   string   `form:"type=email;label=Email address;validate=required,email;transform=trim"`
+	Plan    string   `form:"options=free:Free,pro:Pro;default=free"`
+	Topics  []string `form:"options=go,web"`
+	Rating  float64  `form:"type=number;help=From 1 to 5;validate=min:1,max:5"`
+	Address struct {
+		Street string `form:"placeholder=123 Main St"`
+		City   string
+	}
+}
+
+func TestDefinition_Render(t *testing.T) {
+	def, err := form_builder.ParseDefinition([]byte(surveyJSON))
+	if err != nil {
+		t.Fatalf("ParseDefinition() err = %v; want nil", err)
+	}
+
+	var s survey
+	s.Email = "alice@cc.cc"
+	s.Topics = []string{"web"}
+	s.Address.City = "Lisbon"
+	data := def.Data(map[string]interface{}{
+		"Email":   "alice@cc.cc",
+		"Topics":  []interface{}{"web"},
+		"Address": map[string]interface{}{"City": "Lisbon"},
+	})
+	errors := []form_builder.FieldError{{Field: "Address.City", Message: "We don't deliver there"}}
+
+	want, err := form_builder.NewForm(&s, form_builder.WithErrors(errors...))
+	if err != nil {
+		t.Fatalf("NewForm(struct) err = %v; want nil", err)
+	}
+	got, err := form_builder.NewForm(data, form_builder.WithErrors(errors...))
+	if err != nil {
+		t.Fatalf("NewForm(definition) err = %v; want nil", err)
+	}
+
+	wantHTML, err := want.Render()
+	if err != nil {
+		t.Fatalf("Render() err = %v; want nil", err)
+	}
+	gotHTML, err := got.Render()
+	if err != nil {
+		t.Fatalf("Render() err = %v; want nil", err)
+	}
+	if gotHTML != wantHTML {
+		t.Errorf("Render() = %s; want the same as the struct: %s", gotHTML, wantHTML)
+	}
+}
+
+func TestDefinition_Bind(t *testing.T) {
+	def, err := form_builder.ParseDefinition([]byte(surveyJSON))
+	if err != nil {
+		t.Fatalf("ParseDefinition() err = %v; want nil", err)
+	}
+
+	values := make(map[string]interface{})
+	data := def.Data(values)
+	errors, err := form_builder.Bind(url.Values{
+		"Email":          {"  alice@cc.cc "},
+		"Topics":         {"go", "rust"},
+		"Rating":         {"7"},
+		"Address.Street": {"1 Rua Augusta"},
+	}, data, form_builder.WithDefaults())
+	if err != nil || len(errors) > 0 {
+		t.Fatalf("Bind() = %v, %v; want no errors", errors, err)
+	}
+
+	want := map[string]interface{}{
+		"Email":   "alice@cc.cc",
+		"Plan":    "free",
+		"Topics":  []string{"go", "rust"},
+		"Rating":  7.0,
+		"Address": map[string]interface{}{"Street": "1 Rua Augusta", "City": ""},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Bind() values = %v; want %v", values, want)
+	}
+
+	errors, err = form_builder.Validate(data)
+	if err != nil {
+		t.Fatalf("Validate() err = %v; want nil", err)
+	}
+	wantErrors := form_builder.FieldErrors{
+		{Field: "Topics", Code: "invalid_option", Params: map[string]interface{}{"label": "Topics"}},
+		{Field: "Rating", Code: "too_large", Params: map[string]interface{}{"label": "Rating", "max": 5.0}},
+	}
+	if !reflect.DeepEqual(errors, wantErrors) {
+		t.Errorf("Validate() = %v; want %v", errors, wantErrors)
+	}
+}
+
+func TestDefinition_BindInvalid(t *testing.T) {
+	def, err := form_builder.ParseDefinition([]byte(surveyJSON))
+	if err != nil {
+		t.Fatalf("ParseDefinition() err = %v; want nil", err)
+	}
+
+	values := make(map[string]interface{})
+	errors, err := form_builder.Bind(url.Values{"Rating": {"lots"}}, def.Data(values))
+	if err != nil {
+		t.Fatalf("Bind() err = %v; want nil", err)
+	}
+	if len(errors) != 1 || errors[0].Code != "not_number" {
+		t.Errorf("Bind() errors = %v; want one not_number error", errors)
+	}
+	if v, ok := values["Rating"]; ok {
+		t.Errorf("Bind() Rating = %v; want it left unset", v)
+	}
+}
+
+func TestDefinition_multipleDefault(t *testing.T) {
+	def, err := form_builder.ParseDefinition([]byte(`{"fields": [
+		{"name": "Topics", "options": [{"value": "go"}, {"value": "web"}], "multiple": true, "default": "go"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseDefinition() err = %v; want nil", err)
+	}
+	fs, err := form_builder.Fields(def)
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+	if want := []string{"go"}; !reflect.DeepEqual(fs[0].Value, want) {
+		t.Errorf("Fields()[0].Value = %#v; want %#v", fs[0].Value, want)
+	}
+
+	_, err = form_builder.ParseDefinition([]byte(`{"fields": [{"name": "Topics", "multiple": true, "default": ["go"]}]}`))
+	want := "form: default of field Topics must be a single value, not a list"
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseDefinition() err = %v; want %s", err, want)
+	}
+}
+
+func TestParseDefinition_errors(t *testing.T) {
+	tests := map[string]string{
+		"Invalid JSON":       `{"fields": [`,
+		"Missing name":       `{"fields": [{"label": "Name"}]}`,
+		"Dotted name":        `{"fields": [{"name": "a.b"}]}`,
+		"Duplicate name":     `{"fields": [{"name": "a"}, {"name": "a"}]}`,
+		"Unknown rule":       `{"fields": [{"name": "a", "validate": ["shiny"]}]}`,
+		"Unknown transform":  `{"fields": [{"name": "a", "transform": ["shout"]}]}`,
+		"Invalid default":    `{"fields": [{"name": "a", "type": "number", "default": "many"}]}`,
+		"Invalid option":     `{"fields": [{"name": "a", "type": "number", "options": [{"value": "one"}]}]}`,
+		"Invalid group item": `{"fields": [{"name": "g", "fields": [{"name": ""}]}]}`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := form_builder.ParseDefinition([]byte(data))
+			if err == nil {
+				t.Errorf("ParseDefinition() err = nil; want an error")
+			}
+		})
+	}
+}
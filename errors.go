@@ -0,0 +1,114 @@
+package form_builder
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// FieldError is provided as a way to denote errors with specific fields.
+// An empty Field denotes an error with the form as a whole, such as
+// "invalid credentials".
+//
+// Errors built by hand usually just set Message. Errors returned by Bind
+// and Validate instead set a Code, such as "too_short", and the Params
+// needed to describe it, so the message can be translated when it is
+// rendered.
+//
+// FieldError implements error, so it can be returned from the domain layer
+// and recovered with AsFieldErrors.
+type FieldError struct {
+	Field   string
+	Message string
+	Code    string
+	Params  map[string]interface{}
+}
+
+// Error returns the error's English message, prefixed by its field.
+func (fe FieldError) Error() string {
+	if fe.Field == "" {
+		return fe.Text(nil)
+	}
+	return fe.Field + ": " + fe.Text(nil)
+}
+
+// FieldErrors returns fe as the only error of a FieldErrors.
+func (fe FieldError) FieldErrors() FieldErrors {
+	return FieldErrors{fe}
+}
+
+// Text returns the message to show for the error. If tr knows the error's
+// Code that translation is used, followed by Message, and finally the
+// default English message for the Code. Params that are message keys, such
+// as a label of "@user.email", are translated too. tr may be nil.
+func (fe FieldError) Text(tr Translator) string {
+	params := localizeParams(tr, fe.Params)
+	if fe.Code != "" && tr != nil {
+		if msg := tr.Translate(fe.Code, params); msg != "" {
+			return msg
+		}
+	}
+	if fe.Message != "" || fe.Code == "" {
+		return fe.Message
+	}
+	msg, ok := defaultMessages[fe.Code]
+	if !ok {
+		return fe.Code
+	}
+	return interpolate(msg, params)
+}
+
+// MarshalJSON encodes the error with its English message, so API clients
+// get the same text a rendered form would show.
+func (fe FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Field   string                 `json:"field,omitempty"`
+		Message string                 `json:"message"`
+		Code    string                 `json:"code,omitempty"`
+		Params  map[string]interface{} `json:"params,omitempty"`
+	}{fe.Field, fe.Text(nil), fe.Code, fe.Params})
+}
+
+// FieldErrors is a list of FieldError that is itself an error, so a whole
+// set of validation failures can be returned as one error value.
+type FieldErrors []FieldError
+
+// Error joins the messages of every error.
+func (fes FieldErrors) Error() string {
+	msgs := make([]string, len(fes))
+	for i, fe := range fes {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// FieldErrors returns fes.
+func (fes FieldErrors) FieldErrors() FieldErrors {
+	return fes
+}
+
+// MarshalJSON encodes fes as a JSON array, which is empty rather than null
+// when there are no errors.
+func (fes FieldErrors) MarshalJSON() ([]byte, error) {
+	if fes == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]FieldError(fes))
+}
+
+// FieldErrorer is implemented by errors that carry field errors.
+// FieldError and FieldErrors implement it, and domain errors can too.
+type FieldErrorer interface {
+	FieldErrors() FieldErrors
+}
+
+// AsFieldErrors finds the first error in err's chain that implements
+// FieldErrorer, as errors.As does, and returns its field errors. It reports
+// false if there is none.
+func AsFieldErrors(err error) (FieldErrors, bool) {
+	var fe FieldErrorer
+	if errors.As(err, &fe) {
+		return fe.FieldErrors(), true
+	}
+	return nil, false
+}
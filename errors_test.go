@@ -0,0 +1,126 @@
+package form_builder_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form_builder"
+	"reflect"
+	"testing"
+)
+
+type signupError struct {
+	email string
+}
+
+func (e *signupError) Error() string {
+	return "signup failed"
+}
+
+func (e *signupError) FieldErrors() form_builder.FieldErrors {
+	return form_builder.FieldErrors{{Field: "email", Message: e.email + " is already taken"}}
+}
+
+func TestFieldErrors_Error(t *testing.T) {
+	fes := form_builder.FieldErrors{
+		{Field: "Name", Code: "required", Params: map[string]interface{}{"label": "Name"}},
+		{Message: "Invalid credentials"},
+	}
+
+	want := "Name: Name is required; Invalid credentials"
+	if got := fes.Error(); got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+}
+
+func TestFieldErrors_MarshalJSON(t *testing.T) {
+	tests := map[string]struct {
+		fes  form_builder.FieldErrors
+		want string
+	}{
+		"nil": {
+			want: `[]`,
+		},
+		"errors": {
+			fes: form_builder.FieldErrors{
+				{Field: "Name", Code: "too_short", Params: map[string]interface{}{"label": "Name", "min": 3}},
+				{Message: "Invalid credentials"},
+			},
+			want: `[{"field":"Name","message":"Name must be at least 3 characters","code":"too_short","params":{"label":"Name","min":3}},{"message":"Invalid credentials"}]`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(tc.fes)
+			if err != nil {
+				t.Fatalf("Marshal() err = %v; want nil", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Marshal() = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAsFieldErrors(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		want   form_builder.FieldErrors
+		wantOK bool
+	}{
+		"nil": {},
+		"plain error": {
+			err: errors.New("boom"),
+		},
+		"FieldError": {
+			err:    form_builder.FieldError{Field: "Name", Message: "is taken"},
+			want:   form_builder.FieldErrors{{Field: "Name", Message: "is taken"}},
+			wantOK: true,
+		},
+		"wrapped FieldErrors": {
+			err:    fmt.Errorf("saving user: %w", form_builder.FieldErrors{{Field: "Name", Message: "is taken"}}),
+			want:   form_builder.FieldErrors{{Field: "Name", Message: "is taken"}},
+			wantOK: true,
+		},
+		"wrapped FieldErrorer": {
+			err:    fmt.Errorf("saving user: %w", &signupError{email: "alice@cc.cc"}),
+			want:   form_builder.FieldErrors{{Field: "email", Message: "alice@cc.cc is already taken"}},
+			wantOK: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := form_builder.AsFieldErrors(tc.err)
+			if ok != tc.wantOK {
+				t.Errorf("AsFieldErrors() ok = %v; want %v", ok, tc.wantOK)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("AsFieldErrors() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithError(t *testing.T) {
+	strct := struct {
+		Email string `form:"name=email"`
+	}{}
+
+	form, err := form_builder.NewForm(strct,
+		form_builder.WithError(fmt.Errorf("saving user: %w", &signupError{email: "alice@cc.cc"})),
+		form_builder.WithError(errors.New("Please try again later")),
+		form_builder.WithError(nil),
+	)
+	if err != nil {
+		t.Fatalf("NewForm() err = %v; want nil", err)
+	}
+
+	if got := form.Fields()[0].Errors; !reflect.DeepEqual(got, []string{"alice@cc.cc is already taken"}) {
+		t.Errorf("Fields()[0].Errors = %q; want the domain field error", got)
+	}
+	if got := form.Errors(); !reflect.DeepEqual(got, []string{"Please try again later"}) {
+		t.Errorf("Errors() = %q; want the plain error", got)
+	}
+}
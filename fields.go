@@ -1,42 +1,174 @@
 package form_builder
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 )
 
-type field struct {
+var (
+	errNotStruct  = errors.New("form: only structs are supported")
+	errInvalidTag = errors.New("form: invalid struct tag")
+	errDuplicate  = errors.New("form: duplicate input name")
+)
+
+// Field is a fully resolved description of a single form input. It is the
+// value passed to the field template by HTML, and it is what Fields returns
+// for callers that want to lay out or serialize a form themselves.
+type Field struct {
+	// ID is a unique element ID derived from Name, for use in id, <label
+	// for> and links to the field. ErrorID and HelpID are the IDs of the
+	// elements holding the field's errors and help text. They are only set
+	// by Fields.
+	ID          string
+	ErrorID     string
+	HelpID      string
 	Label       string
 	Name        string
 	Type        string
 	Placeholder string
-	Value       interface{}
-	Errors      []string
+	// Help is a short description shown with the input, such as "We'll
+	// never share your email".
+	Help string
+	// Accept lists the types of file a file input takes, and Multiple is
+	// set if it takes more than one file.
+	Accept   string
+	Multiple bool
+	// Options are the choices of a select, set by the options tag.
+	Options []Choice
+	Value   interface{}
+	Errors  []string
+}
+
+// Choice is a single option of a select. The options tag lists choices
+// separated by commas, each a value optionally followed by a colon and its
+// label, such as options=draft:Draft,live:Published. The label defaults to
+// the value.
+type Choice struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+}
+
+func parseChoices(tag string) []Choice {
+	var choices []Choice
+	for _, s := range strings.Split(tag, ",") {
+		c := Choice{Value: s, Label: s}
+		if i := strings.Index(s, ":"); i >= 0 {
+			c.Value, c.Label = s[:i], s[i+1:]
+		}
+		choices = append(choices, c)
+	}
+	return choices
+}
+
+// FormHelper is implemented by field values that provide their own help
+// text. A help struct tag on the field takes precedence over it.
+type FormHelper interface {
+	FormHelp() string
+}
+
+// Invalid reports whether the field has any errors, for use in attributes
+// such as aria-invalid.
+func (f Field) Invalid() bool {
+	return len(f.Errors) > 0
+}
+
+// DescribedBy returns the value of the field's aria-describedby attribute:
+// the IDs of the elements that describe it, separated by spaces.
+func (f Field) DescribedBy() string {
+	var ids []string
+	if f.Invalid() {
+		ids = append(ids, f.ErrorID)
+	}
+	if f.Help != "" {
+		ids = append(ids, f.HelpID)
+	}
+	return strings.Join(ids, " ")
+}
+
+// Selected reports whether value is one of the field's values, for marking
+// the selected options of a select.
+func (f Field) Selected(value string) bool {
+	if f.Value == nil {
+		return false
+	}
+	raws, err := formatValues(reflect.ValueOf(f.Value))
+	if err != nil {
+		return false
+	}
+	for _, raw := range raws {
+		if raw == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Checked reports whether a checkbox is checked: whether its value is true,
+// or for values other than bools, whether it is set at all.
+func (f Field) Checked() bool {
+	if f.Value == nil {
+		return false
+	}
+	v := reflect.ValueOf(f.Value)
+	if v.Kind() == reflect.Bool {
+		return v.Bool()
+	}
+	return !v.IsZero()
 }
 
-func (f *field) apply(tags map[string]string) {
+func (f *Field) apply(tags map[string]string) {
 	if v, ok := tags["label"]; ok {
 		f.Label = v
 	}
-	if v, ok := tags["name"]; ok {
-		f.Name = v
-	}
 	if v, ok := tags["placeholder"]; ok {
 		f.Placeholder = v
 	}
 	if v, ok := tags["type"]; ok {
 		f.Type = v
 	}
+	if v, ok := tags["help"]; ok {
+		f.Help = v
+	}
+	if v, ok := tags["accept"]; ok {
+		f.Accept = v
+	}
 }
 
-func (f *field) setErrors(errors []FieldError) {
+func (f *Field) setErrors(errors []FieldError, tr Translator) {
 	for _, ferr := range errors {
 		if ferr.Field == f.Name {
-			f.Errors = append(f.Errors, ferr.Error)
+			f.Errors = append(f.Errors, ferr.Text(tr))
 		}
 	}
 }
 
+// Fields returns the field descriptors for strct, which must be a struct, a
+// pointer to one, or a Definition or its Data. Any options, such as
+// WithErrors, are applied to the returned fields, so they match exactly
+// what HTML would render. Two fields with the same input name are an error,
+// unless DisambiguateNames is given.
+func Fields(strct interface{}, opts ...Option) ([]Field, error) {
+	cfg := newConfig(opts)
+
+	fs, err := parseFields(strct, cfg)
+	if err != nil {
+		return nil, err
+	}
+	finishFields(fs, cfg)
+	return fs, nil
+}
+
+// finishFields assigns IDs and errors to fields once they have all been
+// resolved, since IDs must be unique across the whole form.
+func finishFields(fs []Field, cfg *config) {
+	assignIDs(fs, cfg.idPrefix)
+	for i := range fs {
+		fs[i].setErrors(cfg.errors, cfg.translator)
+	}
+}
+
 func valueOf(val interface{}) reflect.Value {
 	var refVal reflect.Value
 	switch value := val.(type) {
@@ -59,57 +191,306 @@ func valueOf(val interface{}) reflect.Value {
 	return refVal
 }
 
-func fields(strct interface{}, parentNames ...string) []field {
-	refVal := valueOf(strct)
+func parseFields(strct interface{}, cfg *config) ([]Field, error) {
+	var formFields []Field
+	err := walkForm(strct, false, cfg, func(l leaf) error {
+		f, err := fieldOf(l, cfg)
+		if err != nil {
+			return err
+		}
+		formFields = append(formFields, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return formFields, nil
+}
+
+// fieldOf resolves the field descriptor for a single leaf.
+func fieldOf(l leaf, cfg *config) (Field, error) {
+	refValForm := valueOf(l.value)
+
+	f := Field{
+		Label:       l.field.Name,
+		Name:        l.name,
+		Type:        "text",
+		Placeholder: l.field.Name,
+		Value:       refValForm.Interface(),
+	}
+	if helper, ok := formHelper(refValForm); ok {
+		f.Help = helper.FormHelp()
+	}
+	if isFile(l.value.Type()) {
+		// Browsers never prefill file inputs, so there is no value to
+		// show.
+		f.Type = "file"
+		f.Value = nil
+		f.Multiple = l.value.Type() == fileHeadersType
+	}
+	if choices, ok := choicesOf(l); ok {
+		f.Type = "select"
+		f.Options = choices
+		f.Multiple = refValForm.Kind() == reflect.Slice
+	}
+	f.apply(l.tags)
 
-	// Make sure the value is struct
-	if refVal.Kind() != reflect.Struct {
-		panic("Oh oh. Only Struct is supported!")
+	// Fall back to the default tag, converted to the field's type, for
+	// fields that haven't been given a value.
+	if def, ok := l.tags["default"]; ok && refValForm.IsZero() {
+		value, err := convert(refValForm.Type(), def)
+		if err != nil {
+			return Field{}, fmt.Errorf("form: invalid default %q on field %s: %v", def, l.field.Name, err)
+		}
+		f.Value = value
 	}
 
-	typ := refVal.Type()
+	// Values with their own text encoding, such as time.Time, are shown as
+	// that text, just as Values encodes them.
+	if v := reflect.ValueOf(f.Value); f.Value != nil && isTextMarshaler(v) {
+		raws, err := formatValues(v)
+		if err != nil {
+			return Field{}, fmt.Errorf("form: can't encode field %s: %v", l.field.Name, err)
+		}
+		f.Value = raws[0]
+	}
 
-	var formFields []field
+	f.resolve(cfg)
+	return f, nil
+}
+
+// resolve applies the options that depend on the request rather than the
+// struct: it localizes f's text and shows any submitted input as its value.
+// Passwords are left empty once input is given, so a submission rendered
+// again doesn't put them back in the page's HTML.
+func (f *Field) resolve(cfg *config) {
+	f.Label = localize(cfg.translator, f.Label)
+	f.Placeholder = localize(cfg.translator, f.Placeholder)
+	f.Help = localize(cfg.translator, f.Help)
+	for i := range f.Options {
+		f.Options[i].Label = localize(cfg.translator, f.Options[i].Label)
+	}
+
+	if cfg.input != nil && f.Type == "password" {
+		f.Value = nil
+	}
+	if raws, ok := cfg.input[f.Name]; ok && f.Value != nil {
+		f.Value = inputValue(reflect.TypeOf(f.Value), raws)
+	}
+}
+
+// inputValue returns the value to show for a field of type t that was
+// submitted as raws: the converted value if raws can be bound to t, or
+// exactly what the user typed if they can't, so they can fix it rather than
+// type it again. That is every value typed for a slice, such as the options
+// of a multiple select, and the first for anything else.
+func inputValue(t reflect.Type, raws []string) interface{} {
+	v := reflect.New(t).Elem()
+	err := setValues(v, raws)
+	if err != nil {
+		if t.Kind() == reflect.Slice {
+			return append([]string(nil), raws...)
+		}
+		if len(raws) == 0 {
+			return ""
+		}
+		return raws[0]
+	}
+	return valueOf(v).Interface()
+}
+
+// leaf is a single input found by walk.
+type leaf struct {
+	field reflect.StructField
+	value reflect.Value
+	// path is the dotted path of Go field names leading to the field.
+	path string
+	// name is the input name: the same as path, unless a name tag
+	// overrides it.
+	name string
+	tags map[string]string
+	// choices are the options of a leaf that doesn't come from a struct
+	// field, which are given as they are rather than in an options tag.
+	choices []Choice
+	// invalid, if set, is called by Bind when the submitted value can't be
+	// converted, for leaves whose value is stored somewhere else once fn
+	// returns.
+	invalid func()
+}
+
+// leafWalker is implemented by forms that aren't backed by a struct, such
+// as a Definition, to produce the same leaves walk does.
+type leafWalker interface {
+	walkLeaves(alloc bool, fn func(leaf) error) error
+}
+
+// walkForm calls fn for every input of strct, which is a struct, a pointer
+// to one, or a leafWalker. Inputs must have unique names, as uniqueNames
+// makes sure.
+func walkForm(strct interface{}, alloc bool, cfg *config, fn func(leaf) error) error {
+	var walkLeaves func(alloc bool, fn func(leaf) error) error
+	if w, ok := strct.(leafWalker); ok {
+		walkLeaves = w.walkLeaves
+	} else {
+		refVal := valueOf(strct)
+		if refVal.Kind() != reflect.Struct {
+			return errNotStruct
+		}
+		walkLeaves = func(alloc bool, fn func(leaf) error) error {
+			return walk(refVal, alloc, nil, fn)
+		}
+	}
+	return walkLeaves(alloc, uniqueNames(cfg, walkLeaves, fn))
+}
+
+// uniqueNames wraps fn so that it returns an error, naming the Go field
+// paths of both fields, for a leaf whose input name an earlier leaf already
+// has, since their values would overwrite each other once submitted. With
+// DisambiguateNames the leaf is renamed after its path instead, avoiding
+// the name of every leaf walkLeaves finds, so no field keeps the name it
+// was given only to lose it to a renamed one.
+func uniqueNames(cfg *config, walkLeaves func(alloc bool, fn func(leaf) error) error, fn func(leaf) error) func(leaf) error {
+	written := make(map[string]bool)
+	if cfg.disambiguate {
+		// Errors are left to the walk the returned func is used in, which
+		// runs into them again.
+		walkLeaves(false, func(l leaf) error {
+			written[l.name] = true
+			return nil
+		})
+	}
+
+	paths := make(map[string]string)
+	return func(l leaf) error {
+		if prev, ok := paths[l.name]; ok {
+			if !cfg.disambiguate {
+				return fmt.Errorf("%w %q on fields %s and %s", errDuplicate, l.name, prev, l.path)
+			}
+			name := l.path
+			for i := 2; paths[name] != "" || written[name]; i++ {
+				name = fmt.Sprintf("%s_%d", l.path, i)
+			}
+			l.name = name
+		}
+		paths[l.name] = l.path
+		return fn(l)
+	}
+}
+
+// choicesOf returns the options of l, if it has any.
+func choicesOf(l leaf) ([]Choice, bool) {
+	if l.choices != nil {
+		return append([]Choice(nil), l.choices...), true
+	}
+	if opts, ok := l.tags["options"]; ok {
+		return parseChoices(opts), true
+	}
+	return nil, false
+}
+
+// walk calls fn for every exported field of the struct v, descending into
+// nested structs and pointers to structs. This is the single place that
+// decides which fields become inputs and what they are named, so
+// rendering and binding always agree.
+//
+// If alloc is true, nil pointers to nested structs are allocated in place
+// so their fields can be set; otherwise they are walked as zero values.
+func walk(v reflect.Value, alloc bool, parentNames []string, fn func(leaf) error) error {
+	typ := v.Type()
 	for i := 0; i < typ.NumField(); i++ {
-		typeForm := typ.Field(i)
-		refValForm := valueOf(refVal.Field(i))
+		sf := typ.Field(i)
+		fv := v.Field(i)
 
 		// Check unexported field
-		if !refValForm.CanInterface() {
+		if !fv.CanInterface() {
 			continue
 		}
 
+		names := append(parentNames[:len(parentNames):len(parentNames)], sf.Name)
+
 		// Supports nested fields
-		if refValForm.Kind() == reflect.Struct {
-			nestedParentNames := append(parentNames, typeForm.Name)
-			nestedFields := fields(refValForm.Interface(), nestedParentNames...)
-			formFields = append(formFields, nestedFields...)
+		if isStruct(sf.Type) {
+			if alloc && fv.Kind() == reflect.Ptr && fv.IsNil() {
+				fv.Set(reflect.New(sf.Type.Elem()))
+			}
+			err := walk(valueOf(fv), alloc, names, fn)
+			if err != nil {
+				return err
+			}
 			continue
 		}
 
-		names := append(parentNames, typeForm.Name)
-		name := strings.Join(names, ".")
+		tags, err := tagsOf(sf)
+		if err != nil {
+			return err
+		}
 
-		f := field{
-			Label:       typeForm.Name,
-			Name:        name,
-			Type:        "text",
-			Placeholder: typeForm.Name,
-			Value:       refValForm.Interface(),
+		path := strings.Join(names, ".")
+		name := path
+		if v, ok := tags["name"]; ok {
+			name = v
 		}
 
-		f.apply(parseTags(typeForm))
+		err = fn(leaf{field: sf, value: fv, path: path, name: name, tags: tags})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		formFields = append(formFields, f)
+// isStruct reports whether walk descends into fields of type t. Structs
+// with their own text encoding, such as time.Time, are inputs like any
+// other value instead.
+func isStruct(t reflect.Type) bool {
+	if isFile(t) {
+		return false
 	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// formHelper returns v, or a pointer to v, if it implements FormHelper.
+func formHelper(v reflect.Value) (FormHelper, bool) {
+	if helper, ok := v.Interface().(FormHelper); ok {
+		return helper, true
+	}
+	if v.CanAddr() {
+		helper, ok := v.Addr().Interface().(FormHelper)
+		return helper, ok
+	}
+	return nil, false
+}
 
-	return formFields
+func tagsOf(rsf reflect.StructField) (map[string]string, error) {
+	tags, err := ParseTag(rsf.Tag.Get("form"))
+	if err != nil {
+		return nil, fmt.Errorf("%w on field %s", err, rsf.Name)
+	}
+	return tags, nil
+}
+
+// tagKeys are the keys a form struct tag may set.
+var tagKeys = []string{
+	"label", "name", "placeholder", "type", "help", "accept", "maxsize",
+	"options", "default", "validate", "transform",
 }
 
-func parseTags(rsf reflect.StructField) map[string]string {
-	rawTag := rsf.Tag.Get("form")
+// TagKeys returns the keys a form struct tag may set. Any other key is
+// ignored.
+func TagKeys() []string {
+	return append([]string(nil), tagKeys...)
+}
+
+// ParseTag parses the value of a form struct tag, such as
+// "label=Email;validate=required", into its keys and values. It returns
+// nil for an empty tag.
+func ParseTag(rawTag string) (map[string]string, error) {
 	if len(rawTag) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	result := make(map[string]string)
@@ -118,12 +499,12 @@ func parseTags(rsf reflect.StructField) map[string]string {
 	for _, tag := range tags {
 		kv := strings.Split(tag, "=")
 		if len(kv) != 2 {
-			panic("form: invalid struct tag")
+			return nil, fmt.Errorf("%w %q", errInvalidTag, rawTag)
 		}
 
 		k, v := kv[0], kv[1]
 		result[k] = v
 	}
 
-	return result
+	return result, nil
 }
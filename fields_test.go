@@ -2,11 +2,13 @@ package form_builder
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 	"testing"
+	"time"
 )
 
-func TestParseTags(t *testing.T) {
+func TestTagsOf(t *testing.T) {
 	tests := map[string]struct {
 		arg  reflect.StructField
 		want map[string]string
@@ -36,30 +38,41 @@ func TestParseTags(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := parseTags(tc.arg)
+			got, err := tagsOf(tc.arg)
+			if err != nil {
+				t.Fatalf("tagsOf() err = %v; want nil", err)
+			}
 			if len(got) != len(tc.want) {
-				t.Errorf("parseTags() len = %d, want %d", len(got), len(tc.want))
+				t.Errorf("tagsOf() len = %d, want %d", len(got), len(tc.want))
 			}
 
 			for k, v := range tc.want {
 				gotVal, ok := got[k]
 				if !ok {
-					t.Errorf("parseTags() missing key %q", k)
+					t.Errorf("tagsOf() missing key %q", k)
 					continue
 				}
 				if gotVal != v {
-					t.Errorf("parseTags()[%q] = %q; want %q", k, gotVal, v)
+					t.Errorf("tagsOf()[%q] = %q; want %q", k, gotVal, v)
 				}
 				delete(got, k)
 			}
 
 			for gotKey, gotVal := range got {
-				t.Errorf("parseTags() extra key %q, value = %q", gotKey, gotVal)
+				t.Errorf("tagsOf() extra key %q, value = %q", gotKey, gotVal)
 			}
 		})
 	}
 }
 
+type helpEmail string
+
+func (helpEmail) FormHelp() string { return "We'll never share your email" }
+
+type helpPhone string
+
+func (p *helpPhone) FormHelp() string { return "Current value: " + string(*p) }
+
 func TestFields(t *testing.T) {
 	var nilStructPointer *struct {
 		Name string
@@ -68,7 +81,7 @@ func TestFields(t *testing.T) {
 
 	type testStructs struct {
 		strct interface{}
-		want  []field
+		want  []Field
 	}
 
 	tests := map[string]testStructs{
@@ -76,7 +89,7 @@ func TestFields(t *testing.T) {
 			strct: struct {
 				Name string
 			}{},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -90,7 +103,7 @@ func TestFields(t *testing.T) {
 			strct: struct {
 				FullName string
 			}{},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "FullName",
 					Name:        "FullName",
@@ -106,7 +119,7 @@ func TestFields(t *testing.T) {
 				Email string
 				Age   int
 			}{},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -140,7 +153,7 @@ func TestFields(t *testing.T) {
 				Email: "alice@cc.cc",
 				Age:   25,
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -174,7 +187,7 @@ func TestFields(t *testing.T) {
 				email: "alice@cc.cc",
 				Age:   25,
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -199,7 +212,7 @@ func TestFields(t *testing.T) {
 				Name: "Alice Smith",
 				Age:  25,
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -218,7 +231,7 @@ func TestFields(t *testing.T) {
 		},
 		"Nil pointers with a struct type should be supported": {
 			strct: nilStructPointer,
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -240,7 +253,7 @@ func TestFields(t *testing.T) {
 				Name *string
 				Age  *int
 			}{},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -274,7 +287,7 @@ func TestFields(t *testing.T) {
 					Zip:    12345,
 				},
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -322,7 +335,7 @@ func TestFields(t *testing.T) {
 					},
 				},
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "C1",
 					Name:        "A.B.C1",
@@ -359,7 +372,7 @@ func TestFields(t *testing.T) {
 					Zip:    12345,
 				},
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "Name",
 					Name:        "Name",
@@ -402,7 +415,7 @@ func TestFields(t *testing.T) {
 			}{
 				PlaceholderTest: "value and placeholder",
 			},
-			want: []field{
+			want: []Field{
 				{
 					Label:       "This is custom",
 					Name:        "LabelTest",
@@ -440,17 +453,104 @@ func TestFields(t *testing.T) {
 				},
 			},
 		},
+		"Default values are used for zero values": {
+			strct: struct {
+				Country  string   `form:"default=US"`
+				Quantity int      `form:"default=1"`
+				Price    *float64 `form:"default=9.99"`
+				Given    string   `form:"default=US"`
+			}{
+				Given: "CA",
+			},
+			want: []Field{
+				{
+					Label:       "Country",
+					Name:        "Country",
+					Type:        "text",
+					Placeholder: "Country",
+					Value:       "US",
+				},
+				{
+					Label:       "Quantity",
+					Name:        "Quantity",
+					Type:        "text",
+					Placeholder: "Quantity",
+					Value:       1,
+				},
+				{
+					Label:       "Price",
+					Name:        "Price",
+					Type:        "text",
+					Placeholder: "Price",
+					Value:       9.99,
+				},
+				{
+					Label:       "Given",
+					Name:        "Given",
+					Type:        "text",
+					Placeholder: "Given",
+					Value:       "CA",
+				},
+			},
+		},
+		"Help text": {
+			strct: &struct {
+				Tag      string `form:"help=Your full name"`
+				Email    helpEmail
+				Phone    helpPhone
+				Override helpEmail `form:"help=Overridden"`
+			}{
+				Phone: "555",
+			},
+			want: []Field{
+				{
+					Label:       "Tag",
+					Name:        "Tag",
+					Type:        "text",
+					Placeholder: "Tag",
+					Help:        "Your full name",
+					Value:       "",
+				},
+				{
+					Label:       "Email",
+					Name:        "Email",
+					Type:        "text",
+					Placeholder: "Email",
+					Help:        "We'll never share your email",
+					Value:       helpEmail(""),
+				},
+				{
+					Label:       "Phone",
+					Name:        "Phone",
+					Type:        "text",
+					Placeholder: "Phone",
+					Help:        "Current value: 555",
+					Value:       helpPhone("555"),
+				},
+				{
+					Label:       "Override",
+					Name:        "Override",
+					Type:        "text",
+					Placeholder: "Override",
+					Help:        "Overridden",
+					Value:       helpEmail(""),
+				},
+			},
+		},
 	}
 
 	for key, tc := range tests {
 		t.Run(fmt.Sprintf("%v", key), func(t *testing.T) {
-			got := fields(tc.strct)
+			got, err := parseFields(tc.strct, &config{})
+			if err != nil {
+				t.Fatalf("parseFields() err = %v; want nil", err)
+			}
 			if !reflect.DeepEqual(got, tc.want) {
-				t.Errorf("fields():\n  got %v;\n want %v", got, tc.want)
+				t.Errorf("parseFields():\n  got %v;\n want %v", got, tc.want)
 			}
 
 			if len(got) != len(tc.want) {
-				t.Errorf("fields(): got %d; want %d", len(got), len(tc.want))
+				t.Errorf("parseFields(): got %d; want %d", len(got), len(tc.want))
 			}
 
 			for i, gotField := range got {
@@ -488,8 +588,8 @@ func TestFields(t *testing.T) {
 
 func TestFields_labels(t *testing.T) {
 
-	hasLabels := func(labels ...string) func(*testing.T, []field) {
-		return func(t *testing.T, fields []field) {
+	hasLabels := func(labels ...string) func(*testing.T, []Field) {
+		return func(t *testing.T, fields []Field) {
 			if len(fields) != len(labels) {
 				t.Errorf("fields() len: got %d; want %d", len(fields), len(labels))
 			}
@@ -502,8 +602,8 @@ func TestFields_labels(t *testing.T) {
 		}
 	}
 
-	hasValues := func(values ...interface{}) func(*testing.T, []field) {
-		return func(t *testing.T, fields []field) {
+	hasValues := func(values ...interface{}) func(*testing.T, []Field) {
+		return func(t *testing.T, fields []Field) {
 			if len(fields) != len(values) {
 				t.Errorf("fields() len: got %d; want %d", len(fields), len(values))
 			}
@@ -516,13 +616,13 @@ func TestFields_labels(t *testing.T) {
 		}
 	}
 
-	check := func(checks ...func(*testing.T, []field)) []func(*testing.T, []field) {
+	check := func(checks ...func(*testing.T, []Field)) []func(*testing.T, []Field) {
 		return checks
 	}
 
 	type testStructs struct {
 		strct  interface{}
-		checks []func(*testing.T, []field)
+		checks []func(*testing.T, []Field)
 	}
 
 	tests := map[string]testStructs{
@@ -551,7 +651,10 @@ func TestFields_labels(t *testing.T) {
 
 	for key, tc := range tests {
 		t.Run(fmt.Sprintf("%v", key), func(t *testing.T) {
-			got := fields(tc.strct)
+			got, err := parseFields(tc.strct, &config{})
+			if err != nil {
+				t.Fatalf("parseFields() err = %v; want nil", err)
+			}
 
 			for _, check := range tc.checks {
 				check(t, got)
@@ -571,19 +674,15 @@ func TestFields_invalidTypes(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(fmt.Sprintf("%T", tc.notAStruct), func(t *testing.T) {
-
-			defer func() {
-				if err := recover(); err == nil {
-					t.Errorf("fields(%v) did not panic", tc.notAStruct)
-				}
-			}()
-
-			fields(tc.notAStruct)
+			_, err := parseFields(tc.notAStruct, &config{})
+			if err == nil {
+				t.Errorf("parseFields(%v) err = nil; want an error", tc.notAStruct)
+			}
 		})
 	}
 }
 
-func TestParseTags_invalidStructTypes(t *testing.T) {
+func TestTagsOf_invalidStructTypes(t *testing.T) {
 	tests := []struct {
 		arg reflect.StructField
 	}{
@@ -592,14 +691,173 @@ func TestParseTags_invalidStructTypes(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(string(tc.arg.Tag), func(t *testing.T) {
+			_, err := tagsOf(tc.arg)
+			if err == nil {
+				t.Errorf("tagsOf() err = nil; want an error")
+			}
+		})
+	}
+}
 
-			defer func() {
-				if err := recover(); err == nil {
-					t.Errorf("parseTags() did not panic")
-				}
-			}()
+func TestFieldsPublic(t *testing.T) {
+	strct := struct {
+		Email    string `form:"name=email"`
+		Password string
+	}{
+		Email: "alice@cc.cc",
+	}
+
+	got, err := Fields(strct, WithErrors(
+		FieldError{Field: "email", Message: "is taken"},
+		FieldError{Field: "Password", Message: "is too short"},
+		FieldError{Field: "Password", Message: "needs a digit"},
+	))
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
 
-			parseTags(tc.arg)
+	want := []Field{
+		{
+			ID:          "email",
+			ErrorID:     "email-error",
+			HelpID:      "email-help",
+			Label:       "Email",
+			Name:        "email",
+			Type:        "text",
+			Placeholder: "Email",
+			Value:       "alice@cc.cc",
+			Errors:      []string{"is taken"},
+		},
+		{
+			ID:          "Password",
+			ErrorID:     "Password-error",
+			HelpID:      "Password-help",
+			Label:       "Password",
+			Name:        "Password",
+			Type:        "text",
+			Placeholder: "Password",
+			Value:       "",
+			Errors:      []string{"is too short", "needs a digit"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields():\n  got %v;\n want %v", got, want)
+	}
+}
+
+func TestFieldsPublic_errors(t *testing.T) {
+	tests := map[string]interface{}{
+		"not a struct": "string",
+		"nil":          nil,
+		"invalid tag": struct {
+			Name string `form:"invalid"`
+		}{},
+		"invalid default": struct {
+			Quantity int `form:"default=one"`
+		}{},
+		"duplicate name": struct {
+			Email   string
+			Contact string `form:"name=Email"`
+		}{},
+	}
+
+	for name, strct := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Fields(strct)
+			if err == nil {
+				t.Errorf("Fields() err = nil; want an error")
+			}
+			if got != nil {
+				t.Errorf("Fields() = %v; want nil", got)
+			}
 		})
 	}
 }
+
+func TestFieldsPublic_duplicateNames(t *testing.T) {
+	type contact struct {
+		Email string `form:"name=email"`
+	}
+	strct := struct {
+		Email   string `form:"name=email"`
+		Billing contact
+		Other   string `form:"name=Billing.Email"`
+	}{}
+
+	_, err := Fields(strct)
+	want := `form: duplicate input name "email" on fields Email and Billing.Email`
+	if err == nil || err.Error() != want {
+		t.Errorf("Fields() err = %v; want %s", err, want)
+	}
+
+	got, err := Fields(strct, DisambiguateNames())
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+	var names []string
+	for _, f := range got {
+		names = append(names, f.Name)
+	}
+	if want := []string{"email", "Billing.Email_2", "Billing.Email"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Fields() names = %v; want %v", names, want)
+	}
+}
+
+func TestFieldsPublic_textUnmarshaler(t *testing.T) {
+	strct := struct {
+		When  time.Time
+		Name  string
+		Since *time.Time `form:"default=2024-05-01T10:00:00Z"`
+	}{}
+
+	got, err := Fields(strct)
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+	var names []string
+	var values []interface{}
+	for _, f := range got {
+		names = append(names, f.Name)
+		values = append(values, f.Value)
+	}
+	if want := []string{"When", "Name", "Since"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Fields() names = %v; want %v", names, want)
+	}
+	if want := []interface{}{"", "", "2024-05-01T10:00:00Z"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Fields() values = %#v; want %#v", values, want)
+	}
+}
+
+func TestFieldsPublic_input(t *testing.T) {
+	strct := struct {
+		Name  string
+		Age   int
+		Score *float64
+		Tags  []int
+		Picks []int `form:"options=1,2,3"`
+		Kept  string
+		Pass  string `form:"type=password"`
+	}{
+		Kept: "unchanged",
+		Pass: "stored",
+	}
+
+	got, err := Fields(strct, WithInput(url.Values{
+		"Name":  {"Alice"},
+		"Age":   {"abc"},
+		"Score": {"9.5"},
+		"Tags":  {"1", "2"},
+		"Picks": {"1", "x", "3"},
+		"Pass":  {"hunter2"},
+	}))
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+
+	want := []interface{}{"Alice", "abc", 9.5, []int{1, 2}, []string{"1", "x", "3"}, "unchanged", nil}
+	for i, f := range got {
+		if !reflect.DeepEqual(f.Value, want[i]) {
+			t.Errorf("Fields()[%d].Value = %#v; want %#v", i, f.Value, want[i])
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package form_builder
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// isFile reports whether t is bound from uploaded files rather than from
+// values.
+func isFile(t reflect.Type) bool {
+	return t == fileHeaderType || t == fileHeadersType
+}
+
+// bindFiles sets l, a *multipart.FileHeader or []*multipart.FileHeader
+// field, from the files uploaded for it. Files that break the field's
+// maxsize or accept tags are left out and reported as FieldErrors.
+func bindFiles(l leaf, files []*multipart.FileHeader) ([]FieldError, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	var maxSize int64
+	if v, ok := l.tags["maxsize"]; ok {
+		n, err := parseSize(v)
+		if err != nil {
+			return nil, fmt.Errorf("form: invalid maxsize %q on field %s", v, l.field.Name)
+		}
+		maxSize = n
+	}
+	accept := l.tags["accept"]
+
+	var ferrs []FieldError
+	var valid []*multipart.FileHeader
+	for _, fh := range files {
+		params := map[string]interface{}{"label": labelOf(l), "file": fh.Filename}
+		if maxSize > 0 && fh.Size > maxSize {
+			params["max"] = l.tags["maxsize"]
+			ferrs = append(ferrs, FieldError{Field: l.name, Code: "file_too_large", Params: params})
+			continue
+		}
+		if accept != "" {
+			typ, err := sniff(fh)
+			if err != nil {
+				return nil, err
+			}
+			if !accepts(accept, fh.Filename, typ) {
+				params["accept"] = accept
+				ferrs = append(ferrs, FieldError{Field: l.name, Code: "file_type", Params: params})
+				continue
+			}
+		}
+		valid = append(valid, fh)
+	}
+
+	switch l.value.Type() {
+	case fileHeaderType:
+		if len(valid) > 0 {
+			l.value.Set(reflect.ValueOf(valid[0]))
+		}
+	case fileHeadersType:
+		l.value.Set(reflect.ValueOf(valid))
+	}
+	return ferrs, nil
+}
+
+// sniff returns the content type of an uploaded file, detected from its
+// contents rather than trusting the type the browser sent.
+func sniff(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := f.Read(buf)
+	if err != nil && n == 0 && fh.Size > 0 {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// accepts reports whether a file matches accept, a comma separated list of
+// content types, wildcards such as image/* and extensions such as .pdf, as
+// in the HTML accept attribute. contentType is the type sniffed from the
+// file's contents. Since the file name is chosen by the client, a file only
+// matches an extension if its contents are of the type registered for the
+// extension too.
+func accepts(accept, filename, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	for _, a := range strings.Split(accept, ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch {
+		case strings.HasPrefix(a, "."):
+			if strings.HasSuffix(strings.ToLower(filename), a) && sniffedAs(mediaType, a) {
+				return true
+			}
+		case strings.HasSuffix(a, "/*"):
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+		case a == mediaType:
+			return true
+		}
+	}
+	return false
+}
+
+// sniffedAs reports whether contents sniffed as mediaType can be those of a
+// file with the extension ext. Sniffing can't tell text formats apart, so
+// plain text stands for any text type. Extensions of no known type, or of
+// a type sniffing can't detect, never match.
+func sniffedAs(mediaType, ext string) bool {
+	want, _, err := mime.ParseMediaType(mime.TypeByExtension(ext))
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(want, "text/") {
+		return mediaType == "text/plain"
+	}
+	return want == mediaType
+}
+
+// parseSize parses a size such as "512", "500KB" or "5MB". Units are powers
+// of 1024.
+func parseSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	}
+
+	s = strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
@@ -0,0 +1,152 @@
+package form_builder_test
+
+import (
+	"bytes"
+	"form_builder"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var pngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type upload struct {
+	Title   string
+	Avatar  *multipart.FileHeader   `form:"maxsize=1KB;accept=image/png,image/jpeg"`
+	Papers  []*multipart.FileHeader `form:"accept=.pdf,text/*"`
+	Ignored *multipart.FileHeader
+}
+
+func newUploadRequest(t *testing.T, files map[string][][2]string) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("Title", "Holiday")
+	for field, fs := range files {
+		for _, f := range fs {
+			w, err := mw.CreateFormFile(field, f[0])
+			if err != nil {
+				t.Fatalf("CreateFormFile() err = %v", err)
+			}
+			w.Write([]byte(f[1]))
+		}
+	}
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestBindRequest_files(t *testing.T) {
+	r := newUploadRequest(t, map[string][][2]string{
+		"Avatar": {{"me.png", string(pngData)}},
+		"Papers": {{"notes.txt", "plain text"}, {"thesis.pdf", "%PDF-1.4"}, {"virus.exe", "MZ\x90\x00"}, {"virus.pdf", "MZ\x90\x00"}},
+	})
+
+	var got upload
+	errors, err := form_builder.BindRequest(r, &got)
+	if err != nil {
+		t.Fatalf("BindRequest() err = %v; want nil", err)
+	}
+
+	wantErrors := form_builder.FieldErrors{{
+		Field:  "Papers",
+		Code:   "file_type",
+		Params: map[string]interface{}{"label": "Papers", "file": "virus.exe", "accept": ".pdf,text/*"},
+	}, {
+		Field:  "Papers",
+		Code:   "file_type",
+		Params: map[string]interface{}{"label": "Papers", "file": "virus.pdf", "accept": ".pdf,text/*"},
+	}}
+	if !reflect.DeepEqual(errors, wantErrors) {
+		t.Errorf("BindRequest() errors = %v; want %v", errors, wantErrors)
+	}
+
+	if got.Title != "Holiday" {
+		t.Errorf("Title = %q; want %q", got.Title, "Holiday")
+	}
+	if got.Avatar == nil || got.Avatar.Filename != "me.png" {
+		t.Errorf("Avatar = %v; want me.png", got.Avatar)
+	}
+	var papers []string
+	for _, fh := range got.Papers {
+		papers = append(papers, fh.Filename)
+	}
+	if strings.Join(papers, ",") != "notes.txt,thesis.pdf" {
+		t.Errorf("Papers = %v; want notes.txt and thesis.pdf", papers)
+	}
+	if got.Ignored != nil {
+		t.Errorf("Ignored = %v; want nil", got.Ignored)
+	}
+}
+
+func TestBindRequest_fileLimits(t *testing.T) {
+	tests := map[string]struct {
+		filename string
+		data     string
+		wantCode string
+	}{
+		"too large": {
+			filename: "big.png",
+			data:     string(pngData) + strings.Repeat("x", 1024),
+			wantCode: "file_too_large",
+		},
+		"content doesn't match the type": {
+			filename: "fake.png",
+			data:     "<html>not an image</html>",
+			wantCode: "file_type",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newUploadRequest(t, map[string][][2]string{"Avatar": {{tc.filename, tc.data}}})
+
+			var got upload
+			errors, err := form_builder.BindRequest(r, &got)
+			if err != nil {
+				t.Fatalf("BindRequest() err = %v; want nil", err)
+			}
+			if len(errors) != 1 || errors[0].Code != tc.wantCode {
+				t.Errorf("BindRequest() errors = %v; want one %s error", errors, tc.wantCode)
+			}
+			if got.Avatar != nil {
+				t.Errorf("Avatar = %v; want nil", got.Avatar)
+			}
+		})
+	}
+}
+
+func TestNewForm_files(t *testing.T) {
+	form, err := form_builder.NewForm(&upload{})
+	if err != nil {
+		t.Fatalf("NewForm() err = %v; want nil", err)
+	}
+	if form.Enctype != "multipart/form-data" {
+		t.Errorf("Enctype = %q; want multipart/form-data", form.Enctype)
+	}
+
+	fields := form.Fields()
+	if len(fields) != 4 {
+		t.Fatalf("len(Fields()) = %d; want 4", len(fields))
+	}
+	avatar, papers := fields[1], fields[2]
+	if avatar.Type != "file" || avatar.Accept != "image/png,image/jpeg" || avatar.Multiple || avatar.Value != nil {
+		t.Errorf("Avatar field = %+v; want a single file input", avatar)
+	}
+	if papers.Type != "file" || !papers.Multiple {
+		t.Errorf("Papers field = %+v; want a multiple file input", papers)
+	}
+
+	html, err := form.RenderFields()
+	if err != nil {
+		t.Fatalf("RenderFields() err = %v; want nil", err)
+	}
+	want := `<input id="Papers" type="file" name="Papers" placeholder="Papers" accept=".pdf,text/*" multiple>`
+	if !strings.Contains(string(html), want) {
+		t.Errorf("RenderFields() = %s; want it to contain %s", html, want)
+	}
+}
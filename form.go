@@ -0,0 +1,249 @@
+package form_builder
+
+import (
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// DefaultTemplate is the field template used by a Form when no template is
+// provided with WithTemplate.
+var DefaultTemplate = template.Must(template.New("field").Parse(`
+<label for="{{.ID}}">{{.Label}}</label>
+{{if eq .Type "select" -}}
+<select id="{{.ID}}" name="{{.Name}}"
+	{{- if .Multiple}} multiple{{end}}
+	{{- if .Invalid}} aria-invalid="true"{{end}}
+	{{- with .DescribedBy}} aria-describedby="{{.}}"{{end}}>
+{{- range .Options}}
+<option value="{{.Value}}"{{if $.Selected .Value}} selected{{end}}>{{.Label}}</option>
+{{- end}}
+</select>
+{{- else -}}
+<input id="{{.ID}}" type="{{.Type}}" name="{{.Name}}" placeholder="{{.Placeholder}}"
+	{{- with .Value}} value="{{.}}"{{end}}
+	{{- with .Accept}} accept="{{.}}"{{end}}
+	{{- if .Multiple}} multiple{{end}}
+	{{- if and (eq .Type "checkbox") .Checked}} checked{{end}}
+	{{- if .Invalid}} aria-invalid="true"{{end}}
+	{{- with .DescribedBy}} aria-describedby="{{.}}"{{end}}>
+{{- end}}
+{{- with .Help}}
+<p id="{{$.HelpID}}" class="help">{{.}}</p>
+{{- end}}
+{{- if .Invalid}}
+<div id="{{.ErrorID}}" class="error">
+{{- range .Errors}}
+<p>{{.}}</p>
+{{- end}}
+</div>
+{{- end}}`))
+
+var (
+	formOpenTpl = template.Must(template.New("open").Parse(
+		`<form{{with .ID}} id="{{.}}"{{end}}{{with .Action}} action="{{.}}"{{end}} method="{{.Method}}"{{with .Enctype}} enctype="{{.}}"{{end}}>
+		{{- with .CSRFToken}}
+<input type="hidden" name="{{$.CSRFField}}" value="{{.}}">
+		{{- end}}`))
+	formErrorsTpl = template.Must(template.New("errors").Parse(`
+{{- if not .Valid}}
+<div class="error-summary" role="alert" aria-labelledby="{{with .ID}}{{.}}-{{end}}error-summary-title" tabindex="-1">
+<h2 id="{{with .ID}}{{.}}-{{end}}error-summary-title">{{.ErrorTitle}}</h2>
+<ul>
+{{- range .Errors}}
+<li>{{.}}</li>
+{{- end}}
+{{- range .Fields}}{{$id := .ID}}
+{{- range .Errors}}
+<li><a href="#{{$id}}">{{.}}</a></li>
+{{- end}}
+{{- end}}
+</ul>
+</div>
+{{- end}}`))
+	formSubmitTpl = template.Must(template.New("submit").Parse(`
+{{- with .}}
+<button type="submit">{{.}}</button>
+{{- end}}`))
+)
+
+// Form bundles the fields built from a struct with the form-level state
+// needed to render a complete <form> element: where it is submitted, how,
+// and any errors that don't belong to a single field.
+//
+// A Form is meant to be built once per request with NewForm and passed
+// through to whatever renders the page.
+type Form struct {
+	// ID is the form's element ID. It defaults to the prefix passed to
+	// WithIDPrefix, and is also used to keep the error summary's IDs unique.
+	ID      string
+	Action  string
+	Method  string
+	Enctype string
+	// Submit is the label of the submit button. No button is rendered if it
+	// is empty.
+	Submit string
+	// ErrorTitle is the heading of the error summary.
+	ErrorTitle string
+	// CSRFField and CSRFToken are the name and value of the hidden CSRF
+	// input rendered after the opening tag. They are set by WithCSRF.
+	CSRFField string
+	CSRFToken string
+
+	fields []Field
+	errors []string
+	input  url.Values
+	tpl    *template.Template
+}
+
+// NewForm builds a Form from strct, which must be a struct or a pointer to
+// one. Fields are resolved the same way Fields resolves them, and are
+// rendered with DefaultTemplate unless WithTemplate is provided.
+//
+// Errors passed with WithErrors that have an empty Field, or whose Field
+// doesn't match any field's Name, become form-level errors. Forms with a
+// file input are given the multipart/form-data Enctype they need.
+func NewForm(strct interface{}, opts ...Option) (*Form, error) {
+	cfg := newConfig(opts)
+
+	fs, err := Fields(strct, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl := cfg.tpl
+	if tpl == nil {
+		tpl = DefaultTemplate
+	}
+
+	form := &Form{
+		Method:     "post",
+		Submit:     "Submit",
+		ErrorTitle: "There is a problem",
+		fields:     fs,
+		input:      cfg.input,
+		tpl:        tpl,
+	}
+	if cfg.idPrefix != "" {
+		form.ID = sanitizeID(cfg.idPrefix)
+	}
+	for _, field := range fs {
+		if field.Type == "file" {
+			form.Enctype = "multipart/form-data"
+		}
+	}
+	if cfg.csrf != nil {
+		form.CSRFField = cfg.csrf.fieldName()
+		form.CSRFToken, err = cfg.csrf.Token(cfg.csrfReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, ferr := range UnmatchedErrors(fs, cfg.errors) {
+		form.errors = append(form.errors, ferr.Text(cfg.translator))
+	}
+	return form, nil
+}
+
+// Fields returns the form's field descriptors.
+func (f *Form) Fields() []Field {
+	return f.fields
+}
+
+// Errors returns the form-level errors: those added with AddError, and any
+// error that didn't belong to a field.
+func (f *Form) Errors() []string {
+	return f.errors
+}
+
+// Input returns the raw values submitted for the form, as set by
+// WithInput.
+func (f *Form) Input() url.Values {
+	return f.input
+}
+
+// Valid reports whether the form has no errors, either on its fields or at
+// the form level.
+func (f *Form) Valid() bool {
+	if len(f.errors) > 0 {
+		return false
+	}
+	for _, field := range f.fields {
+		if len(field.Errors) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddError adds msg to every field whose Name is field. If field is empty,
+// or no field has that name, msg is added as a form-level error instead.
+func (f *Form) AddError(field, msg string) {
+	matched := false
+	if field != "" {
+		for i := range f.fields {
+			if f.fields[i].Name == field {
+				f.fields[i].Errors = append(f.fields[i].Errors, msg)
+				matched = true
+			}
+		}
+	}
+	if !matched {
+		f.errors = append(f.errors, msg)
+	}
+}
+
+// RenderOpen renders the opening <form> tag, followed by the hidden CSRF
+// input if the form has one.
+func (f *Form) RenderOpen() (template.HTML, error) {
+	return execute(formOpenTpl, f)
+}
+
+// RenderErrors renders an error summary listing the form-level errors
+// followed by every field error, each linking to the field it belongs to.
+// Nothing is rendered if the form is valid.
+func (f *Form) RenderErrors() (template.HTML, error) {
+	return execute(formErrorsTpl, f)
+}
+
+// RenderFields renders every field with the form's field template.
+func (f *Form) RenderFields() (template.HTML, error) {
+	var sb strings.Builder
+	for _, field := range f.fields {
+		err := f.tpl.Execute(&sb, field)
+		if err != nil {
+			return "", err
+		}
+	}
+	return template.HTML(sb.String()), nil
+}
+
+// Render renders the whole form: the opening tag, form-level errors, the
+// fields, the submit button and the closing tag.
+func (f *Form) Render() (template.HTML, error) {
+	var sb strings.Builder
+	parts := []func() (template.HTML, error){
+		f.RenderOpen,
+		f.RenderErrors,
+		f.RenderFields,
+		func() (template.HTML, error) { return execute(formSubmitTpl, f.Submit) },
+	}
+	for _, part := range parts {
+		html, err := part()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(html))
+	}
+	sb.WriteString("\n</form>")
+	return template.HTML(sb.String()), nil
+}
+
+func execute(t *template.Template, data interface{}) (template.HTML, error) {
+	var sb strings.Builder
+	err := t.Execute(&sb, data)
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(sb.String()), nil
+}
@@ -0,0 +1,180 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"html/template"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestForm_Render(t *testing.T) {
+	tests := map[string]struct {
+		strct  interface{}
+		opts   []form_builder.Option
+		setup  func(*form_builder.Form)
+		render func(*form_builder.Form) (template.HTML, error)
+		want   string
+	}{
+		"A complete form with errors": {
+			strct: struct {
+				Email    string `form:"label=Email Address;type=email;name=email"`
+				Password string `form:"type=password"`
+			}{
+				Email: "alice@cc.cc",
+			},
+			opts: []form_builder.Option{
+				form_builder.WithErrors(
+					form_builder.FieldError{Field: "Password", Message: "Password is required"},
+					form_builder.FieldError{Message: "Record changed since you loaded it"},
+					form_builder.FieldError{Field: "Username", Message: "Username is taken"},
+				),
+			},
+			setup: func(f *form_builder.Form) {
+				f.Action = "/signup"
+				f.Submit = "Sign up"
+				f.AddError("", "Signups are closing soon")
+			},
+			render: (*form_builder.Form).Render,
+			want:   "TestForm_render.golden",
+		},
+		"Opening tag only": {
+			strct: struct{ Name string }{},
+			opts: []form_builder.Option{
+				form_builder.WithIDPrefix("upload"),
+			},
+			setup: func(f *form_builder.Form) {
+				f.Action = "/upload?a=1&b=2"
+				f.Enctype = "multipart/form-data"
+			},
+			render: (*form_builder.Form).RenderOpen,
+			want:   "TestForm_renderOpen.golden",
+		},
+		"Prefixed IDs and ARIA attributes": {
+			strct: struct {
+				Nested struct {
+					MultiTest string `form:"label=This is nested;type=email;help=Use your work address"`
+				}
+			}{},
+			opts: []form_builder.Option{
+				form_builder.WithIDPrefix("login"),
+				form_builder.WithErrors(
+					form_builder.FieldError{Field: "Nested.MultiTest", Message: "Email is required"},
+					form_builder.FieldError{Message: "Invalid credentials"},
+				),
+			},
+			render: (*form_builder.Form).Render,
+			want:   "TestForm_renderARIA.golden",
+		},
+		"Fields with a custom template": {
+			strct: struct {
+				Name  string
+				Email string
+			}{
+				Name: "Alice Smith",
+			},
+			opts: []form_builder.Option{
+				form_builder.WithTemplate(tplTypeNameValue),
+			},
+			render: (*form_builder.Form).RenderFields,
+			want:   "TestForm_renderFields.golden",
+		},
+		"Selects": {
+			strct: struct {
+				Status string   `form:"options=draft:Draft,live:Published;help=Drafts are only visible to you"`
+				Tags   []string `form:"options=go,web,ux"`
+			}{
+				Tags: []string{"go", "ux"},
+			},
+			opts: []form_builder.Option{
+				form_builder.WithInput(url.Values{"Status": {"live"}}),
+			},
+			render: (*form_builder.Form).RenderFields,
+			want:   "TestForm_renderSelect.golden",
+		},
+		"Checkboxes": {
+			strct: struct {
+				Remember bool   `form:"type=checkbox"`
+				Terms    bool   `form:"type=checkbox"`
+				Updates  string `form:"type=checkbox"`
+			}{
+				Remember: true,
+				Updates:  "weekly",
+			},
+			render: (*form_builder.Form).RenderFields,
+			want:   "TestForm_renderCheckbox.golden",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			form, err := form_builder.NewForm(tc.strct, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewForm() err = %v; want nil", err)
+			}
+			if tc.setup != nil {
+				tc.setup(form)
+			}
+
+			got, err := tc.render(form)
+			if err != nil {
+				t.Fatalf("render err = %v; want nil", err)
+			}
+
+			gotFilename := strings.Replace(tc.want, ".golden", ".got", 1)
+			os.Remove(gotFilename)
+
+			if updateFlag {
+				writeFile(t, tc.want, string(got))
+				t.Logf("Updated golden file %s", tc.want)
+			}
+
+			want := template.HTML(readFile(t, tc.want))
+			if got != want {
+				t.Errorf("render - results do not match golden file.")
+				writeFile(t, gotFilename, string(got))
+				t.Errorf(" To compare run: diff %s %s", gotFilename, tc.want)
+			}
+		})
+	}
+}
+
+func TestForm_AddError(t *testing.T) {
+	form, err := form_builder.NewForm(struct {
+		Name  string
+		Email string `form:"name=email"`
+	}{})
+	if err != nil {
+		t.Fatalf("NewForm() err = %v; want nil", err)
+	}
+	if !form.Valid() {
+		t.Fatalf("Valid() = false; want true for a form without errors")
+	}
+
+	form.AddError("email", "is taken")
+	form.AddError("", "Invalid credentials")
+	form.AddError("Unknown", "Something went wrong")
+
+	if form.Valid() {
+		t.Errorf("Valid() = true; want false")
+	}
+	fields := form.Fields()
+	if got := fields[0].Errors; len(got) != 0 {
+		t.Errorf("Fields()[0].Errors = %v; want none", got)
+	}
+	if got := fields[1].Errors; len(got) != 1 || got[0] != "is taken" {
+		t.Errorf("Fields()[1].Errors = %v; want [is taken]", got)
+	}
+	wantErrors := []string{"Invalid credentials", "Something went wrong"}
+	if got := form.Errors(); strings.Join(got, "|") != strings.Join(wantErrors, "|") {
+		t.Errorf("Errors() = %v; want %v", got, wantErrors)
+	}
+}
+
+func TestNewForm_invalid(t *testing.T) {
+	_, err := form_builder.NewForm("not a struct")
+	if err == nil {
+		t.Errorf("NewForm() err = nil; want an error")
+	}
+}
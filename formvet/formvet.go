@@ -0,0 +1,346 @@
+// Package formvet defines an Analyzer that checks form struct tags, so
+// that mistakes which form_builder would only report at runtime, or
+// silently ignore, are caught by go vet.
+//
+// It parses every form tag with form_builder.ParseTag and reports:
+//
+//   - tags that can't be parsed, such as "label" without a value
+//   - keys form_builder doesn't know, such as "lable"
+//   - tags on nested struct fields, which are ignored since their fields
+//     become the inputs
+//   - types and rules that don't suit the field, such as type=number on a
+//     bool, maxsize on a string or min on a bool
+//   - two fields of a struct, possibly nested, with the same input name
+//
+// It can be run with go vet after building cmd/formvet:
+//
+//	go vet -vettool=$(which formvet) ./...
+package formvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"form_builder"
+)
+
+const doc = `check form struct tags
+
+The formvet analyzer parses the form tags of struct fields with
+form_builder's tag grammar and reports malformed tags, unknown keys, tags
+that don't suit their field's type and input names used more than once in
+a struct.`
+
+// Analyzer checks the form tags of struct fields.
+var Analyzer = &analysis.Analyzer{
+	Name:     "formvet",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	keys := make(map[string]bool)
+	for _, k := range form_builder.TagKeys() {
+		keys[k] = true
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st := n.(*ast.StructType)
+		strct, ok := pass.TypesInfo.Types[st].Type.(*types.Struct)
+		if !ok {
+			return
+		}
+		for _, field := range st.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			checkField(pass, keys, field)
+		}
+		checkNames(pass, st, strct)
+	})
+	return nil, nil
+}
+
+// formTag returns the form tag in the raw struct tag of a field, and
+// whether it has one.
+func formTag(raw string) (string, bool) {
+	return reflect.StructTag(raw).Lookup("form")
+}
+
+func checkField(pass *analysis.Pass, keys map[string]bool, field *ast.Field) {
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+	tag, ok := formTag(raw)
+	if !ok {
+		return
+	}
+	tags, err := form_builder.ParseTag(tag)
+	if err != nil {
+		pass.Reportf(field.Tag.Pos(), "invalid form tag %q: want key=value pairs separated by semicolons", tag)
+		return
+	}
+
+	var unknown []string
+	for k := range tags {
+		if !keys[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	for _, k := range sorted(unknown) {
+		pass.Reportf(field.Tag.Pos(), "unknown form tag key %q", k)
+	}
+
+	typ := pass.TypesInfo.TypeOf(field.Type)
+	if typ == nil {
+		return
+	}
+	if isStruct(typ) {
+		if len(tags) > 0 {
+			pass.Reportf(field.Tag.Pos(), "form tag on struct field %s is ignored; its fields become the inputs", fieldName(field))
+		}
+		return
+	}
+	checkType(pass, field, typ, tags)
+	checkRules(pass, field, typ, tags["validate"])
+}
+
+// checkType reports a type tag, or a tag for file inputs, that doesn't
+// suit typ.
+func checkType(pass *analysis.Pass, field *ast.Field, typ types.Type, tags map[string]string) {
+	file := isFile(typ)
+	for _, k := range []string{"accept", "maxsize"} {
+		if _, ok := tags[k]; ok && !file {
+			pass.Reportf(field.Tag.Pos(), "form tag %s only applies to *multipart.FileHeader fields, not %s", k, typ)
+		}
+	}
+
+	t, ok := tags["type"]
+	if !ok {
+		return
+	}
+	// Inputs of slices have one value for each element.
+	elem := typ
+	if s, ok := deref(typ).Underlying().(*types.Slice); ok {
+		elem = s.Elem()
+	}
+	var fits bool
+	switch t {
+	case "number", "range":
+		fits = is(elem, types.IsNumeric|types.IsString)
+	case "checkbox":
+		// A checked checkbox submits its value, "on" unless it has one,
+		// which strings can hold as well as bools.
+		fits = is(elem, types.IsBoolean|types.IsString)
+	case "file":
+		fits = file
+	default:
+		fits = !file
+	}
+	if !fits {
+		pass.Reportf(field.Tag.Pos(), "form tag type=%s doesn't suit field %s of type %s", t, fieldName(field), typ)
+	}
+}
+
+// checkRules reports builtin rules of a validate tag whose argument is
+// malformed or which can't be applied to typ. Other rules may be
+// registered at runtime, so they aren't checked.
+func checkRules(pass *analysis.Pass, field *ast.Field, typ types.Type, validate string) {
+	if validate == "" {
+		return
+	}
+	for _, s := range strings.Split(validate, ",") {
+		name, arg := s, ""
+		if i := strings.Index(s, ":"); i >= 0 {
+			name, arg = s[:i], s[i+1:]
+		}
+		switch name {
+		case "min", "max":
+			if _, err := strconv.ParseFloat(arg, 64); err != nil {
+				pass.Reportf(field.Tag.Pos(), "form rule %s: %q is not a number", name, arg)
+			} else if !measurable(typ) {
+				pass.Reportf(field.Tag.Pos(), "form rule %s can't be applied to %s", name, typ)
+			}
+		case "email":
+			if !is(typ, types.IsString) {
+				pass.Reportf(field.Tag.Pos(), "form rule email can't be applied to %s", typ)
+			}
+		}
+	}
+}
+
+// input is a field that becomes an input, as form_builder walks a struct.
+type input struct {
+	name string
+	path string
+	// top is the field of the checked struct that input is in.
+	top *types.Var
+}
+
+// checkNames reports fields of strct, including those of nested structs,
+// that have the same input name as an earlier field.
+func checkNames(pass *analysis.Pass, st *ast.StructType, strct *types.Struct) {
+	inputs := inputsOf(strct, nil, nil, nil)
+	seen := make(map[string]input)
+	for _, in := range inputs {
+		prev, ok := seen[in.name]
+		if !ok {
+			seen[in.name] = in
+			continue
+		}
+		// Names clashing within a nested struct declared in this package
+		// are reported on that struct.
+		if prev.top == in.top && local(pass, in.top.Type()) {
+			continue
+		}
+		pass.Reportf(topPos(st, in.top), "duplicate form input name %q: %s and %s", in.name, prev.path, in.path)
+	}
+}
+
+// inputsOf returns the inputs of strct, whose fields are named under
+// parents, in the order form_builder walks them.
+func inputsOf(strct *types.Struct, parents []string, top *types.Var, visiting []*types.Struct) []input {
+	for _, v := range visiting {
+		if v == strct {
+			return nil
+		}
+	}
+	visiting = append(visiting, strct)
+
+	var inputs []input
+	for i := 0; i < strct.NumFields(); i++ {
+		f := strct.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		t := top
+		if t == nil {
+			t = f
+		}
+		names := append(parents[:len(parents):len(parents)], f.Name())
+		if isStruct(f.Type()) {
+			nested := deref(f.Type()).Underlying().(*types.Struct)
+			inputs = append(inputs, inputsOf(nested, names, t, visiting)...)
+			continue
+		}
+
+		path := strings.Join(names, ".")
+		in := input{name: path, path: path, top: t}
+		if raw, ok := formTag(strct.Tag(i)); ok {
+			tags, err := form_builder.ParseTag(raw)
+			if err == nil {
+				if name, ok := tags["name"]; ok {
+					in.name = name
+				}
+			}
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// local reports whether t is a struct declared in the package being
+// checked, whose own fields are checked where it is declared.
+func local(pass *analysis.Pass, t types.Type) bool {
+	named, ok := deref(t).(*types.Named)
+	if !ok {
+		return true
+	}
+	return named.Obj().Pkg() == pass.Pkg
+}
+
+// topPos returns the position of the field v in st.
+func topPos(st *ast.StructType, v *types.Var) token.Pos {
+	for _, field := range st.Fields.List {
+		for _, name := range field.Names {
+			if name.Pos() == v.Pos() {
+				return name.Pos()
+			}
+		}
+		if len(field.Names) == 0 && field.Type.Pos() <= v.Pos() && v.Pos() < field.Type.End() {
+			return field.Type.Pos()
+		}
+	}
+	return st.Pos()
+}
+
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	return types.ExprString(field.Type)
+}
+
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+// isFile reports whether t is *multipart.FileHeader or
+// []*multipart.FileHeader, which form_builder binds uploaded files to.
+func isFile(t types.Type) bool {
+	if s, ok := t.(*types.Slice); ok {
+		t = s.Elem()
+	}
+	p, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := p.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "mime/multipart" && obj.Name() == "FileHeader"
+}
+
+// isStruct reports whether form_builder descends into fields of type t
+// rather than making them an input, as it does for structs without their
+// own text encoding.
+func isStruct(t types.Type) bool {
+	if isFile(t) {
+		return false
+	}
+	t = deref(t)
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	unmarshal := types.NewMethodSet(types.NewPointer(t)).Lookup(nil, "UnmarshalText")
+	return unmarshal == nil
+}
+
+// is reports whether t, or what t points to, has a basic type with any of
+// the info flags.
+func is(t types.Type, info types.BasicInfo) bool {
+	b, ok := deref(t).Underlying().(*types.Basic)
+	return ok && b.Info()&info != 0
+}
+
+// measurable reports whether min and max can be applied to t, which they
+// measure by length or value.
+func measurable(t types.Type) bool {
+	switch deref(t).Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Map:
+		return true
+	}
+	return is(t, types.IsNumeric|types.IsString)
+}
+
+func sorted(s []string) []string {
+	sort.Strings(s)
+	return s
+}
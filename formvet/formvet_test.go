@@ -0,0 +1,104 @@
+package formvet_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"form_builder/formvet"
+)
+
+// wantRe matches the `// want` comments in testdata, which give a pattern
+// for the diagnostic expected on their line.
+var wantRe = regexp.MustCompile("// want `([^`]*)`")
+
+func TestAnalyzer(t *testing.T) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob(filepath.Join("testdata", "src", "a", "*.go"))
+	if err != nil {
+		t.Fatalf("Glob() err = %v; want nil", err)
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("ParseFile() err = %v; want nil", err)
+		}
+		files = append(files, f)
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("a", fset, files, info)
+	if err != nil {
+		t.Fatalf("Check() err = %v; want nil", err)
+	}
+
+	got := make(map[int][]string)
+	pass := &analysis.Pass{
+		Analyzer:  formvet.Analyzer,
+		Fset:      fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf: map[*analysis.Analyzer]interface{}{
+			inspect.Analyzer: inspector.New(files),
+		},
+		Report: func(d analysis.Diagnostic) {
+			line := fset.Position(d.Pos).Line
+			got[line] = append(got[line], d.Message)
+		},
+	}
+	_, err = formvet.Analyzer.Run(pass)
+	if err != nil {
+		t.Fatalf("Run() err = %v; want nil", err)
+	}
+
+	want := make(map[int]*regexp.Regexp)
+	for _, f := range files {
+		for _, group := range f.Comments {
+			for _, c := range group.List {
+				m := wantRe.FindStringSubmatch(c.Text)
+				if m == nil {
+					continue
+				}
+				want[fset.Position(c.Pos()).Line] = regexp.MustCompile(m[1])
+			}
+		}
+	}
+
+	var lines []int
+	for line := range got {
+		lines = append(lines, line)
+	}
+	for line := range want {
+		if _, ok := got[line]; !ok {
+			lines = append(lines, line)
+		}
+	}
+	sort.Ints(lines)
+	for _, line := range lines {
+		msgs, re := got[line], want[line]
+		switch {
+		case re == nil:
+			t.Errorf("line %d: unexpected diagnostic %q", line, strings.Join(msgs, "; "))
+		case len(msgs) != 1 || !re.MatchString(msgs[0]):
+			t.Errorf("line %d: diagnostics = %q; want one matching %q", line, msgs, re)
+		}
+	}
+}
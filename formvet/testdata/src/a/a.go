@@ -0,0 +1,50 @@
+package a
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type Address struct {
+	Street string `form:"label=Street"`
+	Zip    string `form:"label=Zip code"`
+}
+
+type Phones struct {
+	Home   string `form:"name=phone"`
+	Mobile string `form:"name=phone"` // want `duplicate form input name "phone": Home and Mobile`
+}
+
+type Signup struct {
+	Name     string `form:"lable=Name"` // want `unknown form tag key "lable"`
+	Email    string `form:"label"`      // want `invalid form tag "label": want key=value pairs separated by semicolons`
+	Contact  string `form:"name=email"`
+	Other    string `form:"name=email"` // want `duplicate form input name "email": Contact and Other`
+	Address  Address
+	Home     Address               `form:"type=number"` // want `form tag on struct field Home is ignored; its fields become the inputs`
+	Agree    bool                  `form:"type=number"` // want `form tag type=number doesn't suit field Agree of type bool`
+	Age      int                   `form:"type=number;validate=min:18"`
+	Count    int                   `form:"type=checkbox"` // want `form tag type=checkbox doesn't suit field Count of type int`
+	Remember string                `form:"type=checkbox;default=yes"`
+	Avatar   *multipart.FileHeader `form:"type=file;accept=image/*;maxsize=1MB"`
+	Bio      string                `form:"maxsize=1MB"`          // want `form tag maxsize only applies to \*multipart.FileHeader fields, not string`
+	Tags     []string              `form:"validate=max:3,email"` // want `form rule email can't be applied to \[\]string`
+	Scores   []int                 `form:"type=range"`
+	Terms    bool                  `form:"validate=min:1"`   // want `form rule min can't be applied to bool`
+	Score    int                   `form:"validate=max:ten"` // want `form rule max: "ten" is not a number`
+	Joined   int                   `form:"validate=email"`   // want `form rule email can't be applied to int`
+	At       time.Time             `form:"label=Starts at"`
+	Location struct {
+		Zip string `form:"name=postcode"`
+	}
+	Phones   Phones
+	Postcode string `form:"name=postcode"`       // want `duplicate form input name "postcode": Location.Zip and Postcode`
+	Street   string `form:"name=Address.Street"` // want `duplicate form input name "Address.Street": Address.Street and Street`
+	private  string `form:"name=email"`
+	Plain    string `json:"plain"`
+}
+
+type Upload struct {
+	Photo  *multipart.FileHeader `form:"type=text"` // want `form tag type=text doesn't suit field Photo of type \*mime/multipart.FileHeader`
+	Photos []*multipart.FileHeader
+}
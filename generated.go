@@ -0,0 +1,56 @@
+package form_builder
+
+import "strings"
+
+// The functions in this file are called by the methods cmd/formgen
+// generates, so that generated forms behave exactly like reflected ones.
+// They are of little use on their own.
+
+// ResolveFields finishes fields generated by formgen just as Fields finishes
+// the fields it reflects: it localizes them, shows the input given with
+// WithInput, assigns their IDs and attaches their errors.
+func ResolveFields(fs []Field, opts ...Option) []Field {
+	cfg := newConfig(opts)
+	for i := range fs {
+		fs[i].resolve(cfg)
+	}
+	finishFields(fs, cfg)
+	return fs
+}
+
+// AppliesDefaults reports whether opts include WithDefaults.
+func AppliesDefaults(opts ...Option) bool {
+	return newConfig(opts).defaults
+}
+
+// TransformValues applies the transforms called names to raws, as Bind does
+// for a transform tag.
+func TransformValues(raws []string, names ...string) ([]string, error) {
+	fns, err := parseTransforms(strings.Join(names, ","))
+	if err != nil {
+		return nil, err
+	}
+	return applyTransforms(fns, raws), nil
+}
+
+// ResolveFormErrors resolves the Field of each error returned by the
+// FormValidator of a struct nested at the Go field path prefix, as Validate
+// does. paths and names are the Go field path and input name of every
+// field in the form.
+func ResolveFormErrors(errors []FieldError, prefix string, paths, names []string) []FieldError {
+	leaves := make([]leaf, len(paths))
+	for i := range paths {
+		leaves[i] = leaf{path: paths[i], name: names[i]}
+	}
+	var parents []string
+	if prefix != "" {
+		parents = strings.Split(prefix, ".")
+	}
+
+	var ferrs []FieldError
+	for _, ferr := range errors {
+		ferr.Field = resolveField(ferr.Field, parents, leaves)
+		ferrs = append(ferrs, ferr)
+	}
+	return ferrs
+}
@@ -1,6 +1,15 @@
 module form_builder
 
+go 1.18
+
 require (
 	github.com/joncalhoun/twg v0.0.0-20181119031950-e0e5e6593959
+	golang.org/x/text v0.14.0
+	golang.org/x/tools v0.6.0
 	golang.org/x/tools/gopls v0.1.3 // indirect
 )
+
+require (
+	golang.org/x/mod v0.8.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+)
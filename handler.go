@@ -0,0 +1,161 @@
+package form_builder
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+)
+
+// Handler serves a form built from T, which must be a struct type, through
+// the whole render, submit and re-render cycle:
+//
+//   - GET renders the page with an empty form.
+//   - POST binds the submission into a new T, validates it and, if it is
+//     valid, calls OnValid. If OnValid returns no errors the user is
+//     redirected with 303 See Other, so reloading the next page doesn't
+//     submit the form again.
+//   - Otherwise the page is rendered again with the submitted values, exactly
+//     as they were typed, and their errors, with status 422 Unprocessable
+//     Entity.
+type Handler[T any] struct {
+	// Page renders the whole page. It is executed with the *Form, so it
+	// can call {{.Render}} or render the form piece by piece.
+	Page *template.Template
+	// Field renders each field of the form. DefaultTemplate is used if it
+	// is nil.
+	Field *template.Template
+	// OnValid is called with every valid submission. It returns the URL to
+	// redirect to, or errors to show on the form instead. An empty URL
+	// redirects back to the form.
+	OnValid func(ctx context.Context, v *T) (redirect string, errors []FieldError)
+	// Options are passed on to NewForm, Bind and ValidateContext.
+	Options []Option
+	// Translator, if set, returns the Translator for the locale of each
+	// request, such as Catalog.TranslatorFor.
+	Translator func(r *http.Request) Translator
+	// CSRF, if set, adds a CSRF token to the form and verifies it on
+	// submit, before anything is bound. Submissions that fail verification
+	// are rendered again with a form-level error and status 403 Forbidden,
+	// and a CSRF without a Secret or SessionID is a 500 Internal Server
+	// Error.
+	CSRF *CSRF
+}
+
+// NewHandler returns a Handler for T. See Handler for details.
+func NewHandler[T any](page, field *template.Template, onValid func(ctx context.Context, v *T) (string, []FieldError)) *Handler[T] {
+	return &Handler[T]{
+		Page:    page,
+		Field:   field,
+		OnValid: onValid,
+	}
+}
+
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.render(w, r, new(T), nil, nil, http.StatusOK)
+	case http.MethodPost:
+		h.submit(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler[T]) submit(w http.ResponseWriter, r *http.Request) {
+	err := parseRequest(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	// Check the token before binding, so a forged submission never gets as
+	// far as converting values or sniffing uploads.
+	if h.CSRF != nil {
+		err := h.CSRF.Verify(r)
+		if err == ErrInvalidCSRFToken {
+			h.render(w, r, new(T), r.PostForm, []FieldError{{Message: csrfMessage}}, http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	v := new(T)
+	errors, err := BindRequest(r, v, append(h.Options[:len(h.Options):len(h.Options)], WithDefaults())...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	invalid, err := ValidateContext(r.Context(), v, h.Options...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	errors = mergeErrors(errors, invalid)
+
+	if len(errors) == 0 {
+		var redirect string
+		redirect, errors = h.OnValid(r.Context(), v)
+		if len(errors) == 0 {
+			if redirect == "" {
+				redirect = r.URL.String()
+			}
+			http.Redirect(w, r, redirect, http.StatusSeeOther)
+			return
+		}
+	}
+
+	h.render(w, r, v, r.PostForm, errors, http.StatusUnprocessableEntity)
+}
+
+func (h *Handler[T]) render(w http.ResponseWriter, r *http.Request, v *T, input url.Values, errors []FieldError, status int) {
+	opts := append(h.Options[:len(h.Options):len(h.Options)], WithErrors(errors...), WithInput(input))
+	if h.Field != nil {
+		opts = append(opts, WithTemplate(h.Field))
+	}
+	if h.CSRF != nil {
+		opts = append(opts, WithCSRF(h.CSRF, r))
+	}
+	if h.Translator != nil {
+		opts = append(opts, WithTranslator(h.Translator(r)))
+	}
+	form, err := NewForm(v, opts...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Render into a buffer first so a failing template doesn't leave a
+	// half-written page behind a success status.
+	var buf bytes.Buffer
+	err = h.Page.Execute(&buf, form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// mergeErrors appends to errors every error in more whose field has no
+// errors yet. This keeps a field that failed to bind from also reporting
+// that its zero value breaks a rule.
+func mergeErrors(errors, more []FieldError) []FieldError {
+	failed := make(map[string]bool, len(errors))
+	for _, ferr := range errors {
+		failed[ferr.Field] = true
+	}
+	for _, ferr := range more {
+		if !failed[ferr.Field] {
+			errors = append(errors, ferr)
+		}
+	}
+	return errors
+}
@@ -0,0 +1,131 @@
+package form_builder_test
+
+import (
+	"context"
+	"form_builder"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type handlerSignup struct {
+	Email    string `form:"type=email;validate=required,email"`
+	Password string `form:"type=password"`
+	Age      int    `form:"validate=min:18"`
+}
+
+func TestHandler(t *testing.T) {
+	page := template.Must(template.New("page").Parse(`<h1>Sign up</h1>{{.Render}}`))
+
+	var saved *handlerSignup
+	onValid := func(ctx context.Context, v *handlerSignup) (string, []form_builder.FieldError) {
+		if v.Email == "taken@cc.cc" {
+			return "", []form_builder.FieldError{{Field: "Email", Message: "Email is already taken"}}
+		}
+		saved = v
+		return "/welcome", nil
+	}
+	h := form_builder.NewHandler(page, tplTypeNameValue, onValid)
+
+	tests := map[string]struct {
+		method       string
+		form         url.Values
+		wantStatus   int
+		wantLocation string
+		wantBody     []string
+		wantNotBody  []string
+	}{
+		"GET renders an empty form": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody: []string{
+				`<h1>Sign up</h1><form method="post">`,
+				`<input type="email" name="Email">`,
+			},
+		},
+		"Invalid submissions are rendered again": {
+			method: http.MethodPost,
+			form: url.Values{
+				"Email": {"alice"},
+				"Age":   {"abc"},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: []string{
+				`<input type="email" name="Email" value="alice">`,
+				`<input type="text" name="Age" value="abc">`,
+				`Email must be a valid email address`,
+				`Age must be a whole number`,
+			},
+		},
+		"Passwords are not rendered again": {
+			method: http.MethodPost,
+			form: url.Values{
+				"Email":    {"alice"},
+				"Password": {"hunter2"},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: []string{
+				`<input type="password" name="Password">`,
+			},
+			wantNotBody: []string{"hunter2"},
+		},
+		"Errors from OnValid are rendered": {
+			method: http.MethodPost,
+			form: url.Values{
+				"Email": {"taken@cc.cc"},
+				"Age":   {"30"},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: []string{
+				`Email is already taken`,
+			},
+		},
+		"Valid submissions are redirected": {
+			method: http.MethodPost,
+			form: url.Values{
+				"Email": {"alice@cc.cc"},
+				"Age":   {"30"},
+			},
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: "/welcome",
+		},
+		"Other methods are not allowed": {
+			method:     http.MethodPut,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "/signup", strings.NewReader(tc.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("status = %d; want %d", w.Code, tc.wantStatus)
+			}
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Errorf("Location = %q; want %q", got, tc.wantLocation)
+			}
+			for _, want := range tc.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body = %q; want it to contain %q", w.Body.String(), want)
+				}
+			}
+			for _, unwanted := range tc.wantNotBody {
+				if strings.Contains(w.Body.String(), unwanted) {
+					t.Errorf("body = %q; want it not to contain %q", w.Body.String(), unwanted)
+				}
+			}
+		})
+	}
+
+	if saved == nil || saved.Email != "alice@cc.cc" || saved.Age != 30 {
+		t.Errorf("OnValid() saved %+v; want the valid submission", saved)
+	}
+}
@@ -5,10 +5,23 @@ import (
 	"strings"
 )
 
-// FieldError is provided as a way to denote errors with specific fields.
-type FieldError struct {
-	Field string
-	Error string
+// UnmatchedErrors returns the errors that would not be shown next to any of
+// fs, either because their Field is empty or because no field has that
+// Name. HTML only renders errors inline, so callers should render these
+// somewhere else rather than lose them.
+func UnmatchedErrors(fs []Field, errors []FieldError) []FieldError {
+	names := make(map[string]bool, len(fs))
+	for _, f := range fs {
+		names[f.Name] = true
+	}
+
+	var unmatched []FieldError
+	for _, ferr := range errors {
+		if ferr.Field == "" || !names[ferr.Field] {
+			unmatched = append(unmatched, ferr)
+		}
+	}
+	return unmatched
 }
 
 // HTML is used to generate HTML forms/inputs from Go structs. Given a
@@ -34,12 +47,16 @@ type FieldError struct {
 // An example similar to this is shown as the first test case in TestHTML
 // in the html_test.go source file.
 //
-// Note: This does not currently support struct tags, but will eventually
-// in order to support more customization and flexibility.
+// Each input is rendered from the same Field values that Fields returns, so
+// callers who need a custom layout can use Fields directly instead.
 func HTML(t *template.Template, strct interface{}, errors ...FieldError) (template.HTML, error) {
+	fs, err := Fields(strct, WithErrors(errors...))
+	if err != nil {
+		return "", err
+	}
+
 	var inputs []string
-	for _, field := range fields(strct) {
-		field.setErrors(errors)
+	for _, field := range fs {
 		var sb strings.Builder
 		err := t.Execute(&sb, field)
 		if err != nil {
@@ -7,6 +7,7 @@ import (
 	"html/template"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -83,11 +84,11 @@ func TestHTML(t *testing.T) {
 				Password: "badpw",
 			},
 			errors: []form_builder.FieldError{
-				{Field: "EmailAddress", Error: "Email address is already taken"},
-				{Field: "Password", Error: "Password must be between 201 and 210 characters"},
-				{Field: "Password", Error: "Password must contain a greek letter"},
-				{Field: "Password", Error: "Password must be a palindrome"},
-				{Field: "Password", Error: "Password must contain an emoji"},
+				{Field: "EmailAddress", Message: "Email address is already taken"},
+				{Field: "Password", Message: "Password must be between 201 and 210 characters"},
+				{Field: "Password", Message: "Password must contain a greek letter"},
+				{Field: "Password", Message: "Password must be a palindrome"},
+				{Field: "Password", Message: "Password must contain an emoji"},
 			},
 			want: "TestHTML_errors.golden",
 		},
@@ -141,3 +142,73 @@ func readFile(t *testing.T, filename string) []byte {
 
 	return byte
 }
+
+func TestUnmatchedErrors(t *testing.T) {
+	fs, err := form_builder.Fields(struct {
+		Email string `form:"name=email"`
+		Name  string
+	}{})
+	if err != nil {
+		t.Fatalf("Fields() err = %v; want nil", err)
+	}
+
+	errors := []form_builder.FieldError{
+		{Field: "email", Message: "Email is taken"},
+		{Message: "Invalid credentials"},
+		{Field: "Email", Message: "Go field names are not input names"},
+		{Field: "Name", Message: "Name is required"},
+	}
+	want := []form_builder.FieldError{errors[1], errors[2]}
+
+	got := form_builder.UnmatchedErrors(fs, errors)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmatchedErrors() = %v; want %v", got, want)
+	}
+}
+
+func TestFieldError_Text(t *testing.T) {
+	french := form_builder.TranslatorFunc(func(key string, params map[string]interface{}) string {
+		if key == "too_short" {
+			return fmt.Sprintf("%s doit contenir au moins %v caractères", params["label"], params["min"])
+		}
+		return ""
+	})
+
+	tests := map[string]struct {
+		ferr form_builder.FieldError
+		tr   form_builder.Translator
+		want string
+	}{
+		"Plain errors are used as is": {
+			ferr: form_builder.FieldError{Field: "Name", Message: "Name is taken"},
+			tr:   french,
+			want: "Name is taken",
+		},
+		"Codes fall back to English": {
+			ferr: form_builder.FieldError{Code: "too_short", Params: map[string]interface{}{"label": "Password", "min": 8.0}},
+			want: "Password must be at least 8 characters",
+		},
+		"Codes are translated": {
+			ferr: form_builder.FieldError{Code: "too_short", Params: map[string]interface{}{"label": "Mot de passe", "min": 8.0}},
+			tr:   french,
+			want: "Mot de passe doit contenir au moins 8 caractères",
+		},
+		"Unknown translations fall back to Message": {
+			ferr: form_builder.FieldError{Code: "required", Message: "Please fill this in"},
+			tr:   french,
+			want: "Please fill this in",
+		},
+		"Unknown codes are shown as is": {
+			ferr: form_builder.FieldError{Code: "mystery"},
+			want: "mystery",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.ferr.Text(tc.tr); got != tc.want {
+				t.Errorf("Text() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package form_builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Translator turns a message key, such as the Code of a FieldError, and its
+// parameters into text for the reader. It returns "" for keys it doesn't
+// know, so the caller can fall back to a default message.
+type Translator interface {
+	Translate(key string, params map[string]interface{}) string
+}
+
+// TranslatorFunc adapts an ordinary function to a Translator.
+type TranslatorFunc func(key string, params map[string]interface{}) string
+
+// Translate calls fn(key, params).
+func (fn TranslatorFunc) Translate(key string, params map[string]interface{}) string {
+	return fn(key, params)
+}
+
+// localize resolves s through tr if it is a message key, written with a
+// leading "@" as in label=@user.email. Keys tr doesn't know are shown
+// without the "@", so a missing translation is easy to spot but still
+// readable. tr may be nil.
+func localize(tr Translator, s string) string {
+	if !strings.HasPrefix(s, "@") {
+		return s
+	}
+	key := s[1:]
+	if tr != nil {
+		if msg := tr.Translate(key, nil); msg != "" {
+			return msg
+		}
+	}
+	return key
+}
+
+// localizeParams returns params with every string value that is a message
+// key localized, such as the label of a field whose label tag is a key.
+func localizeParams(tr Translator, params map[string]interface{}) map[string]interface{} {
+	var localized map[string]interface{}
+	for k, v := range params {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, "@") {
+			continue
+		}
+		if localized == nil {
+			localized = make(map[string]interface{}, len(params))
+			for k, v := range params {
+				localized[k] = v
+			}
+		}
+		localized[k] = localize(tr, s)
+	}
+	if localized == nil {
+		return params
+	}
+	return localized
+}
+
+// defaultMessages are the English messages for the codes of the errors
+// returned by Bind and Validate, used when there is no Translator or it
+// doesn't know a code.
+var defaultMessages = map[string]string{
+	"required":       "{label} is required",
+	"too_short":      "{label} must be at least {min} characters",
+	"too_long":       "{label} must be at most {max} characters",
+	"too_few":        "{label} must have at least {min} items",
+	"too_many":       "{label} must have at most {max} items",
+	"too_small":      "{label} must be at least {min}",
+	"too_large":      "{label} must be at most {max}",
+	"invalid_email":  "{label} must be a valid email address",
+	"invalid_option": "{label} must be one of the options given",
+	"not_bool":       "{label} must be true or false",
+	"not_integer":    "{label} must be a whole number",
+	"not_number":     "{label} must be a number",
+	"invalid":        "{label} is invalid",
+	"file_too_large": "{file} must be smaller than {max}",
+	"file_type":      "{file} must be a file of type {accept}",
+	"bad_request":    "The form could not be read, please try again",
+}
+
+// interpolate replaces every {name} in msg with params[name].
+func interpolate(msg string, params map[string]interface{}) string {
+	if len(params) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+	pairs := make([]string, 0, 2*len(params))
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
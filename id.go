@@ -0,0 +1,70 @@
+package form_builder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// assignIDs gives every field an element ID derived from its Name, along
+// with the IDs of its error and help elements. IDs only contain letters,
+// digits, '-' and '_' so they are safe to use in CSS selectors and URL
+// fragments, and a numeric suffix is added when an ID, or the ID of its
+// error or help element, is already used by another field or by the
+// form's error summary. A non-empty prefix is prepended to every ID so
+// several forms can share a page.
+func assignIDs(fs []Field, prefix string) {
+	seen := map[string]bool{errorSummaryTitleID(prefix): true}
+	for i := range fs {
+		base := sanitizeID(fs[i].Name)
+		if prefix != "" {
+			base = sanitizeID(prefix) + "-" + base
+		}
+		id := base
+		for n := 2; seen[id] || seen[id+"-error"] || seen[id+"-help"]; n++ {
+			id = base + "-" + strconv.Itoa(n)
+		}
+		fs[i].ID = id
+		fs[i].ErrorID = id + "-error"
+		fs[i].HelpID = id + "-help"
+		seen[fs[i].ID], seen[fs[i].ErrorID], seen[fs[i].HelpID] = true, true, true
+	}
+}
+
+// errorSummaryTitleID returns the ID of the error summary's heading in a
+// form whose ID is the prefix passed to WithIDPrefix, as formErrorsTpl
+// renders it.
+func errorSummaryTitleID(prefix string) string {
+	if prefix == "" {
+		return "error-summary-title"
+	}
+	return sanitizeID(prefix) + "-error-summary-title"
+}
+
+func sanitizeID(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			sb.WriteRune(r)
+			dash = false
+		default:
+			// Collapse any run of other characters, such as the dots in
+			// nested names, into a single dash.
+			if !dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = true
+		}
+	}
+
+	id := strings.TrimSuffix(sb.String(), "-")
+	if id == "" || !isLetter(id[0]) {
+		id = "field-" + id
+	}
+	return strings.TrimSuffix(id, "-")
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,137 @@
+package form_builder
+
+import "testing"
+
+func TestSanitizeID(t *testing.T) {
+	tests := map[string]string{
+		"Name":             "Name",
+		"Address.Street":   "Address-Street",
+		"full_name":        "full_name",
+		"items[0].name":    "items-0-name",
+		"a..b":             "a-b",
+		"email address ":   "email-address",
+		"2fa":              "field-2fa",
+		"":                 "field",
+		"-":                "field",
+		"Ünïcode.Ñame":     "n-code-ame",
+		"Nested.MultiTest": "Nested-MultiTest",
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sanitizeID(name); got != want {
+				t.Errorf("sanitizeID(%q) = %q; want %q", name, got, want)
+			}
+		})
+	}
+}
+
+func TestAssignIDs(t *testing.T) {
+	tests := map[string]struct {
+		prefix string
+		want   []string
+	}{
+		"no prefix": {
+			want: []string{"Address-Street", "Address-Street-2", "Address-Street-3", "Email"},
+		},
+		"prefix": {
+			prefix: "signup form",
+			want:   []string{"signup-form-Address-Street", "signup-form-Address-Street-2", "signup-form-Address-Street-3", "signup-form-Email"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := []Field{
+				{Name: "Address.Street"},
+				{Name: "Address-Street"},
+				{Name: "Address Street"},
+				{Name: "Email"},
+			}
+
+			assignIDs(fs, tc.prefix)
+
+			for i, f := range fs {
+				if f.ID != tc.want[i] {
+					t.Errorf("fs[%d].ID = %q; want %q", i, f.ID, tc.want[i])
+				}
+				if f.ErrorID != tc.want[i]+"-error" {
+					t.Errorf("fs[%d].ErrorID = %q; want %q", i, f.ErrorID, tc.want[i]+"-error")
+				}
+				if f.HelpID != tc.want[i]+"-help" {
+					t.Errorf("fs[%d].HelpID = %q; want %q", i, f.HelpID, tc.want[i]+"-help")
+				}
+			}
+		})
+	}
+}
+
+func TestAssignIDs_reserved(t *testing.T) {
+	tests := map[string]struct {
+		prefix string
+		names  []string
+		want   []string
+	}{
+		"error ID": {
+			names: []string{"x", "x-error"},
+			want:  []string{"x", "x-error-2"},
+		},
+		"error ID first": {
+			names: []string{"x-error", "x"},
+			want:  []string{"x-error", "x-2"},
+		},
+		"help ID": {
+			names: []string{"x-help", "x"},
+			want:  []string{"x-help", "x-2"},
+		},
+		"error summary": {
+			names: []string{"error-summary-title"},
+			want:  []string{"error-summary-title-2"},
+		},
+		"error summary with prefix": {
+			prefix: "signup",
+			names:  []string{"error-summary-title"},
+			want:   []string{"signup-error-summary-title-2"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := make([]Field, len(tc.names))
+			for i, name := range tc.names {
+				fs[i].Name = name
+			}
+
+			assignIDs(fs, tc.prefix)
+
+			for i, f := range fs {
+				if f.ID != tc.want[i] {
+					t.Errorf("fs[%d].ID = %q; want %q", i, f.ID, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestField_DescribedBy(t *testing.T) {
+	f := Field{ID: "email", ErrorID: "email-error", HelpID: "email-help"}
+	if f.Invalid() {
+		t.Errorf("Invalid() = true; want false without errors")
+	}
+	if got := f.DescribedBy(); got != "" {
+		t.Errorf("DescribedBy() = %q; want empty without errors", got)
+	}
+
+	f.Errors = []string{"is required"}
+	if !f.Invalid() {
+		t.Errorf("Invalid() = false; want true with errors")
+	}
+	if got := f.DescribedBy(); got != "email-error" {
+		t.Errorf("DescribedBy() = %q; want %q", got, "email-error")
+	}
+
+	f.Help = "We'll never share your email"
+	if got := f.DescribedBy(); got != "email-error email-help" {
+		t.Errorf("DescribedBy() = %q; want %q", got, "email-error email-help")
+	}
+}
@@ -0,0 +1,73 @@
+// Package conformance holds structs whose methods are generated by
+// cmd/formgen, to check that they behave exactly like the reflection-based
+// functions of form_builder.
+package conformance
+
+import "form_builder"
+
+//go:generate go run form_builder/cmd/formgen -type=Signup,Search -output=conformance_formgen.go
+
+type Status string
+
+type Level uint8
+
+// Nickname provides its own help text.
+type Nickname string
+
+func (n Nickname) FormHelp() string {
+	return "What your friends call you"
+}
+
+type Address struct {
+	Street string `form:"validate=required"`
+	City   string `form:"label=Town;validate=max:20"`
+	Zip    uint16 `form:"name=zip"`
+}
+
+func (a *Address) ValidateForm() []form_builder.FieldError {
+	if a.City == "Atlantis" {
+		return []form_builder.FieldError{{Field: "City", Message: "We don't deliver there"}}
+	}
+	return nil
+}
+
+type Signup struct {
+	Name     string   `form:"label=@signup.name;placeholder=Jane Doe;transform=trim,collapse;validate=required,min:2,max:30"`
+	Email    string   `form:"name=email;type=email;help=We'll never share it;transform=trim,lower;validate=required,email"`
+	Password string   `form:"type=password;validate=min:8"`
+	Confirm  string   `form:"type=password"`
+	Age      int      `form:"default=18;validate=min:18,max:130"`
+	Height   *float64 `form:"validate=min:0.5"`
+	Score    float32
+	Agree    bool `form:"validate=required"`
+	Nick     Nickname
+	Status   Status   `form:"options=draft:Draft,live:@status.live;default=draft"`
+	Level    Level    `form:"options=1,2,3"`
+	Tags     []string `form:"options=go,web,ux;validate=max:2"`
+	Lucky    []int    `form:"validate=min:1"`
+	Referrer *string  `form:"accept=text/plain"`
+	Address  Address
+	Billing  struct {
+		Same bool `form:"label=Same as shipping"`
+	}
+	secret string
+}
+
+func (s *Signup) ValidateForm() []form_builder.FieldError {
+	if s.Password != s.Confirm {
+		return []form_builder.FieldError{{Field: "Confirm", Code: "mismatch"}}
+	}
+	return nil
+}
+
+type Base struct {
+	Page int `form:"default=1;validate=min:1"`
+}
+
+type Search struct {
+	Base
+	Query string `form:"name=q"`
+	Open  *bool
+	// Remember is a checkbox, so its default doesn't apply to Bind.
+	Remember bool `form:"default=true"`
+}
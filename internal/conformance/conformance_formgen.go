@@ -0,0 +1,775 @@
+// Code generated by formgen -type=Signup,Search; DO NOT EDIT.
+
+package conformance
+
+import (
+	"form_builder"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Fields is like form_builder.Fields, without reflection.
+func (s *Signup) Fields(opts ...form_builder.Option) ([]form_builder.Field, error) {
+	fs := make([]form_builder.Field, 0, 18)
+	{
+		value := s.Name
+		f := form_builder.Field{
+			Label:       "@signup.name",
+			Name:        "Name",
+			Type:        "text",
+			Placeholder: "Jane Doe",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Email
+		f := form_builder.Field{
+			Label:       "Email",
+			Name:        "email",
+			Type:        "email",
+			Placeholder: "Email",
+		}
+		f.Help = "We'll never share it"
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Password
+		f := form_builder.Field{
+			Label:       "Password",
+			Name:        "Password",
+			Type:        "password",
+			Placeholder: "Password",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Confirm
+		f := form_builder.Field{
+			Label:       "Confirm",
+			Name:        "Confirm",
+			Type:        "password",
+			Placeholder: "Confirm",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Age
+		f := form_builder.Field{
+			Label:       "Age",
+			Name:        "Age",
+			Type:        "text",
+			Placeholder: "Age",
+		}
+		if value == 0 {
+			value = 18
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		var value float64
+		if s.Height != nil {
+			value = *s.Height
+		}
+		f := form_builder.Field{
+			Label:       "Height",
+			Name:        "Height",
+			Type:        "text",
+			Placeholder: "Height",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Score
+		f := form_builder.Field{
+			Label:       "Score",
+			Name:        "Score",
+			Type:        "text",
+			Placeholder: "Score",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Agree
+		f := form_builder.Field{
+			Label:       "Agree",
+			Name:        "Agree",
+			Type:        "text",
+			Placeholder: "Agree",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Nick
+		f := form_builder.Field{
+			Label:       "Nick",
+			Name:        "Nick",
+			Type:        "text",
+			Placeholder: "Nick",
+		}
+		f.Help = value.FormHelp()
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Status
+		f := form_builder.Field{
+			Label:       "Status",
+			Name:        "Status",
+			Type:        "select",
+			Placeholder: "Status",
+			Options: []form_builder.Choice{
+				{Value: "draft", Label: "Draft"},
+				{Value: "live", Label: "@status.live"},
+			},
+		}
+		if value == "" {
+			value = "draft"
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Level
+		f := form_builder.Field{
+			Label:       "Level",
+			Name:        "Level",
+			Type:        "select",
+			Placeholder: "Level",
+			Options: []form_builder.Choice{
+				{Value: "1", Label: "1"},
+				{Value: "2", Label: "2"},
+				{Value: "3", Label: "3"},
+			},
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Tags
+		f := form_builder.Field{
+			Label:       "Tags",
+			Name:        "Tags",
+			Type:        "select",
+			Placeholder: "Tags",
+			Multiple:    true,
+			Options: []form_builder.Choice{
+				{Value: "go", Label: "go"},
+				{Value: "web", Label: "web"},
+				{Value: "ux", Label: "ux"},
+			},
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Lucky
+		f := form_builder.Field{
+			Label:       "Lucky",
+			Name:        "Lucky",
+			Type:        "text",
+			Placeholder: "Lucky",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		var value string
+		if s.Referrer != nil {
+			value = *s.Referrer
+		}
+		f := form_builder.Field{
+			Label:       "Referrer",
+			Name:        "Referrer",
+			Type:        "text",
+			Placeholder: "Referrer",
+			Accept:      "text/plain",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Address.Street
+		f := form_builder.Field{
+			Label:       "Street",
+			Name:        "Address.Street",
+			Type:        "text",
+			Placeholder: "Street",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Address.City
+		f := form_builder.Field{
+			Label:       "Town",
+			Name:        "Address.City",
+			Type:        "text",
+			Placeholder: "City",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Address.Zip
+		f := form_builder.Field{
+			Label:       "Zip",
+			Name:        "zip",
+			Type:        "text",
+			Placeholder: "Zip",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Billing.Same
+		f := form_builder.Field{
+			Label:       "Same as shipping",
+			Name:        "Billing.Same",
+			Type:        "text",
+			Placeholder: "Same",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	return form_builder.ResolveFields(fs, opts...), nil
+}
+
+// Bind is like form_builder.Bind, without reflection.
+func (s *Signup) Bind(values url.Values, opts ...form_builder.Option) (form_builder.FieldErrors, error) {
+	defaults := form_builder.AppliesDefaults(opts...)
+	var ferrs form_builder.FieldErrors
+	if raws, ok := values["Name"]; ok {
+		raws, err := form_builder.TransformValues(raws, "trim", "collapse")
+		if err != nil {
+			return nil, err
+		}
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Name = raw
+	}
+	if raws, ok := values["email"]; ok {
+		raws, err := form_builder.TransformValues(raws, "trim", "lower")
+		if err != nil {
+			return nil, err
+		}
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Email = raw
+	}
+	if raws, ok := values["Password"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Password = raw
+	}
+	if raws, ok := values["Confirm"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Confirm = raw
+	}
+	if raws, ok := values["Age"]; ok || defaults {
+		if !ok {
+			raws = []string{"18"}
+		}
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Age = 0
+		} else if n, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			code = "not_integer"
+		} else {
+			s.Age = int(n)
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Age", Code: code, Params: map[string]interface{}{"label": "Age"}})
+		}
+	}
+	if raws, ok := values["Height"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Height = nil
+		} else {
+			if s.Height == nil {
+				s.Height = new(float64)
+			}
+			if raw == "" {
+				*s.Height = 0
+			} else if n, err := strconv.ParseFloat(raw, 64); err != nil {
+				code = "not_number"
+			} else {
+				*s.Height = float64(n)
+			}
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Height", Code: code, Params: map[string]interface{}{"label": "Height"}})
+		}
+	}
+	if raws, ok := values["Score"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Score = 0
+		} else if n, err := strconv.ParseFloat(raw, 32); err != nil {
+			code = "not_number"
+		} else {
+			s.Score = float32(n)
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Score", Code: code, Params: map[string]interface{}{"label": "Score"}})
+		}
+	}
+	if raws, ok := values["Agree"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		switch raw {
+		case "":
+			s.Agree = false
+		case "on":
+			s.Agree = true
+		default:
+			if b, err := strconv.ParseBool(raw); err != nil {
+				code = "not_bool"
+			} else {
+				s.Agree = b
+			}
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Agree", Code: code, Params: map[string]interface{}{"label": "Agree"}})
+		}
+	}
+	if raws, ok := values["Nick"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Nick = Nickname(raw)
+	}
+	if raws, ok := values["Status"]; ok || defaults {
+		if !ok {
+			raws = []string{"draft"}
+		}
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Status = Status(raw)
+	}
+	if raws, ok := values["Level"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Level = 0
+		} else if n, err := strconv.ParseUint(raw, 10, 8); err != nil {
+			code = "not_integer"
+		} else {
+			s.Level = Level(n)
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Level", Code: code, Params: map[string]interface{}{"label": "Level"}})
+		}
+	}
+	if raws, ok := values["Tags"]; ok {
+		items := make([]string, len(raws))
+		for i, raw := range raws {
+			items[i] = raw
+		}
+		s.Tags = items
+	}
+	if raws, ok := values["Lucky"]; ok {
+		code := ""
+		items := make([]int, len(raws))
+		for i, raw := range raws {
+			if raw == "" {
+				items[i] = 0
+			} else if n, err := strconv.ParseInt(raw, 10, 64); err != nil {
+				code = "not_integer"
+			} else {
+				items[i] = int(n)
+			}
+			if code != "" {
+				break
+			}
+		}
+		if code == "" {
+			s.Lucky = items
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Lucky", Code: code, Params: map[string]interface{}{"label": "Lucky"}})
+		}
+	}
+	if raws, ok := values["Referrer"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Referrer = nil
+		} else {
+			if s.Referrer == nil {
+				s.Referrer = new(string)
+			}
+			*s.Referrer = raw
+		}
+	}
+	if raws, ok := values["Address.Street"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Address.Street = raw
+	}
+	if raws, ok := values["Address.City"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Address.City = raw
+	}
+	if raws, ok := values["zip"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Address.Zip = 0
+		} else if n, err := strconv.ParseUint(raw, 10, 16); err != nil {
+			code = "not_integer"
+		} else {
+			s.Address.Zip = uint16(n)
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "zip", Code: code, Params: map[string]interface{}{"label": "Zip"}})
+		}
+	}
+	if raws, ok := values["Billing.Same"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		switch raw {
+		case "":
+			s.Billing.Same = false
+		case "on":
+			s.Billing.Same = true
+		default:
+			if b, err := strconv.ParseBool(raw); err != nil {
+				code = "not_bool"
+			} else {
+				s.Billing.Same = b
+			}
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Billing.Same", Code: code, Params: map[string]interface{}{"label": "Same as shipping"}})
+		}
+	}
+	return ferrs, nil
+}
+
+// Validate is like form_builder.Validate, without reflection.
+func (s *Signup) Validate() form_builder.FieldErrors {
+	var ferrs form_builder.FieldErrors
+	{
+		value := s.Name
+		if value == "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Name", Code: "required", Params: map[string]interface{}{"label": "@signup.name"}})
+		}
+		if value != "" && float64(utf8.RuneCountInString(string(value))) < 2 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Name", Code: "too_short", Params: map[string]interface{}{"min": float64(2), "label": "@signup.name"}})
+		}
+		if value != "" && float64(utf8.RuneCountInString(string(value))) > 30 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Name", Code: "too_long", Params: map[string]interface{}{"max": float64(30), "label": "@signup.name"}})
+		}
+	}
+	{
+		value := s.Email
+		if value == "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "email", Code: "required", Params: map[string]interface{}{"label": "Email"}})
+		}
+		if value != "" {
+			if addr, err := mail.ParseAddress(string(value)); err != nil || addr.Address != string(value) {
+				ferrs = append(ferrs, form_builder.FieldError{Field: "email", Code: "invalid_email", Params: map[string]interface{}{"label": "Email"}})
+			}
+		}
+	}
+	{
+		value := s.Password
+		if value != "" && float64(utf8.RuneCountInString(string(value))) < 8 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Password", Code: "too_short", Params: map[string]interface{}{"min": float64(8), "label": "Password"}})
+		}
+	}
+	{
+		value := s.Age
+		if value != 0 && float64(value) < 18 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Age", Code: "too_small", Params: map[string]interface{}{"min": float64(18), "label": "Age"}})
+		}
+		if value != 0 && float64(value) > 130 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Age", Code: "too_large", Params: map[string]interface{}{"max": float64(130), "label": "Age"}})
+		}
+	}
+	{
+		var value float64
+		if s.Height != nil {
+			value = *s.Height
+		}
+		if value != 0 && float64(value) < 0.5 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Height", Code: "too_small", Params: map[string]interface{}{"min": float64(0.5), "label": "Height"}})
+		}
+	}
+	{
+		value := s.Agree
+		if value == false {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Agree", Code: "required", Params: map[string]interface{}{"label": "Agree"}})
+		}
+	}
+	{
+		value := s.Status
+		if value != "" {
+			switch string(value) {
+			case "draft", "live":
+			default:
+				ferrs = append(ferrs, form_builder.FieldError{Field: "Status", Code: "invalid_option", Params: map[string]interface{}{"label": "Status"}})
+			}
+		}
+	}
+	{
+		value := s.Level
+		if value != 0 {
+			switch strconv.FormatUint(uint64(value), 10) {
+			case "1", "2", "3":
+			default:
+				ferrs = append(ferrs, form_builder.FieldError{Field: "Level", Code: "invalid_option", Params: map[string]interface{}{"label": "Level"}})
+			}
+		}
+	}
+	{
+		value := s.Tags
+		if value != nil && float64(len(value)) > 2 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Tags", Code: "too_many", Params: map[string]interface{}{"max": float64(2), "label": "Tags"}})
+		}
+		for _, item := range value {
+			switch string(item) {
+			case "go", "web", "ux":
+				continue
+			}
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Tags", Code: "invalid_option", Params: map[string]interface{}{"label": "Tags"}})
+			break
+		}
+	}
+	{
+		value := s.Lucky
+		if value != nil && float64(len(value)) < 1 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Lucky", Code: "too_few", Params: map[string]interface{}{"min": float64(1), "label": "Lucky"}})
+		}
+	}
+	{
+		value := s.Address.Street
+		if value == "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Address.Street", Code: "required", Params: map[string]interface{}{"label": "Street"}})
+		}
+	}
+	{
+		value := s.Address.City
+		if value != "" && float64(utf8.RuneCountInString(string(value))) > 20 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Address.City", Code: "too_long", Params: map[string]interface{}{"max": float64(20), "label": "Town"}})
+		}
+	}
+	ferrs = append(ferrs, form_builder.ResolveFormErrors(s.ValidateForm(), "", formgenSignupPaths, formgenSignupNames)...)
+	ferrs = append(ferrs, form_builder.ResolveFormErrors(s.Address.ValidateForm(), "Address", formgenSignupPaths, formgenSignupNames)...)
+	return ferrs
+}
+
+var (
+	formgenSignupPaths = []string{"Name", "Email", "Password", "Confirm", "Age", "Height", "Score", "Agree", "Nick", "Status", "Level", "Tags", "Lucky", "Referrer", "Address.Street", "Address.City", "Address.Zip", "Billing.Same"}
+	formgenSignupNames = []string{"Name", "email", "Password", "Confirm", "Age", "Height", "Score", "Agree", "Nick", "Status", "Level", "Tags", "Lucky", "Referrer", "Address.Street", "Address.City", "zip", "Billing.Same"}
+)
+
+// Fields is like form_builder.Fields, without reflection.
+func (s *Search) Fields(opts ...form_builder.Option) ([]form_builder.Field, error) {
+	fs := make([]form_builder.Field, 0, 4)
+	{
+		value := s.Base.Page
+		f := form_builder.Field{
+			Label:       "Page",
+			Name:        "Base.Page",
+			Type:        "text",
+			Placeholder: "Page",
+		}
+		if value == 0 {
+			value = 1
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Query
+		f := form_builder.Field{
+			Label:       "Query",
+			Name:        "q",
+			Type:        "text",
+			Placeholder: "Query",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		var value bool
+		if s.Open != nil {
+			value = *s.Open
+		}
+		f := form_builder.Field{
+			Label:       "Open",
+			Name:        "Open",
+			Type:        "text",
+			Placeholder: "Open",
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	{
+		value := s.Remember
+		f := form_builder.Field{
+			Label:       "Remember",
+			Name:        "Remember",
+			Type:        "text",
+			Placeholder: "Remember",
+		}
+		if value == false {
+			value = true
+		}
+		f.Value = value
+		fs = append(fs, f)
+	}
+	return form_builder.ResolveFields(fs, opts...), nil
+}
+
+// Bind is like form_builder.Bind, without reflection.
+func (s *Search) Bind(values url.Values, opts ...form_builder.Option) (form_builder.FieldErrors, error) {
+	defaults := form_builder.AppliesDefaults(opts...)
+	var ferrs form_builder.FieldErrors
+	if raws, ok := values["Base.Page"]; ok || defaults {
+		if !ok {
+			raws = []string{"1"}
+		}
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Base.Page = 0
+		} else if n, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			code = "not_integer"
+		} else {
+			s.Base.Page = int(n)
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Base.Page", Code: code, Params: map[string]interface{}{"label": "Page"}})
+		}
+	}
+	if raws, ok := values["q"]; ok {
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		s.Query = raw
+	}
+	if raws, ok := values["Open"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		if raw == "" {
+			s.Open = nil
+		} else {
+			if s.Open == nil {
+				s.Open = new(bool)
+			}
+			switch raw {
+			case "":
+				*s.Open = false
+			case "on":
+				*s.Open = true
+			default:
+				if b, err := strconv.ParseBool(raw); err != nil {
+					code = "not_bool"
+				} else {
+					*s.Open = b
+				}
+			}
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Open", Code: code, Params: map[string]interface{}{"label": "Open"}})
+		}
+	}
+	if raws, ok := values["Remember"]; ok {
+		code := ""
+		raw := ""
+		if len(raws) > 0 {
+			raw = raws[0]
+		}
+		switch raw {
+		case "":
+			s.Remember = false
+		case "on":
+			s.Remember = true
+		default:
+			if b, err := strconv.ParseBool(raw); err != nil {
+				code = "not_bool"
+			} else {
+				s.Remember = b
+			}
+		}
+		if code != "" {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Remember", Code: code, Params: map[string]interface{}{"label": "Remember"}})
+		}
+	}
+	return ferrs, nil
+}
+
+// Validate is like form_builder.Validate, without reflection.
+func (s *Search) Validate() form_builder.FieldErrors {
+	var ferrs form_builder.FieldErrors
+	{
+		value := s.Base.Page
+		if value != 0 && float64(value) < 1 {
+			ferrs = append(ferrs, form_builder.FieldError{Field: "Base.Page", Code: "too_small", Params: map[string]interface{}{"min": float64(1), "label": "Page"}})
+		}
+	}
+	return ferrs
+}
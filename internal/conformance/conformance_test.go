@@ -0,0 +1,208 @@
+package conformance
+
+import (
+	"form_builder"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func height(f float64) *float64 { return &f }
+
+func text(s string) *string { return &s }
+
+var signups = map[string]Signup{
+	"Empty": {},
+	"Valid": {
+		Name:     "Jane Doe",
+		Email:    "jane@example.com",
+		Password: "correct horse",
+		Confirm:  "correct horse",
+		Age:      30,
+		Height:   height(1.7),
+		Score:    9.5,
+		Agree:    true,
+		Nick:     "JD",
+		Status:   "live",
+		Level:    2,
+		Tags:     []string{"go"},
+		Lucky:    []int{7},
+		Referrer: text("a friend"),
+		Address:  Address{Street: "1 Main St", City: "Lisbon", Zip: 1000},
+	},
+	"Invalid": {
+		Name:     "J",
+		Email:    "Jane <jane@example.com>",
+		Password: "short",
+		Confirm:  "different",
+		Age:      12,
+		Height:   height(0.1),
+		Status:   "archived",
+		Level:    9,
+		Tags:     []string{"go", "rust", "ux"},
+		Lucky:    []int{},
+		Address:  Address{City: "Atlantis"},
+	},
+}
+
+func TestFields(t *testing.T) {
+	translator := form_builder.TranslatorFunc(func(key string, params map[string]interface{}) string {
+		return map[string]string{"signup.name": "Your name", "status.live": "Published"}[key]
+	})
+	options := map[string][]form_builder.Option{
+		"No options": nil,
+		"Errors and IDs": {
+			form_builder.WithIDPrefix("signup"),
+			form_builder.WithErrors(
+				form_builder.FieldError{Field: "email", Message: "Email is taken"},
+				form_builder.FieldError{Field: "Address.City", Code: "too_long", Params: map[string]interface{}{"label": "Town", "max": 20.0}},
+			),
+		},
+		"Input": {
+			form_builder.WithInput(url.Values{"Age": {"abc"}, "Height": {"2.5"}, "Tags": {"web", "ux"}, "zip": {"99"}}),
+		},
+		"Translator": {form_builder.WithTranslator(translator)},
+	}
+
+	for name, signup := range signups {
+		for optName, opts := range options {
+			t.Run(name+"/"+optName, func(t *testing.T) {
+				signup := signup
+				want, err := form_builder.Fields(&signup, opts...)
+				if err != nil {
+					t.Fatalf("form_builder.Fields() err = %v; want nil", err)
+				}
+				got, err := signup.Fields(opts...)
+				if err != nil {
+					t.Fatalf("Fields() err = %v; want nil", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Fields() = %#v\nwant %#v", got, want)
+				}
+			})
+		}
+	}
+
+	var search Search
+	want, _ := form_builder.Fields(&search)
+	got, _ := search.Fields()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Search.Fields() = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestBind(t *testing.T) {
+	tests := map[string]struct {
+		values url.Values
+		opts   []form_builder.Option
+	}{
+		"Valid": {
+			values: url.Values{
+				"Name":           {"  Jane   Doe "},
+				"email":          {" Jane@Example.com"},
+				"Age":            {"30"},
+				"Height":         {"1.75"},
+				"Score":          {"9.5"},
+				"Agree":          {"on"},
+				"Nick":           {"JD"},
+				"Status":         {"live"},
+				"Level":          {"3"},
+				"Tags":           {"go", "ux"},
+				"Lucky":          {"7", "13"},
+				"Referrer":       {"a friend"},
+				"Address.Street": {"1 Main St"},
+				"zip":            {"1000"},
+				"Billing.Same":   {"true"},
+			},
+		},
+		"Unconvertible": {
+			values: url.Values{
+				"Age":          {"thirty"},
+				"Height":       {"tall"},
+				"Score":        {"1.2.3"},
+				"Agree":        {"maybe"},
+				"Level":        {"300"},
+				"Lucky":        {"7", "x"},
+				"zip":          {"-1"},
+				"Billing.Same": {""},
+			},
+		},
+		"Empty values": {
+			values: url.Values{"Age": {""}, "Height": {""}, "Referrer": {""}, "Tags": {}, "Agree": {""}},
+		},
+		"Defaults": {
+			values: url.Values{"Name": {"Jane"}},
+			opts:   []form_builder.Option{form_builder.WithDefaults()},
+		},
+	}
+
+	for name, tc := range tests {
+		for start, signup := range signups {
+			t.Run(name+"/"+start, func(t *testing.T) {
+				want := copySignup(signup)
+				wantErrors, err := form_builder.Bind(tc.values, &want, tc.opts...)
+				if err != nil {
+					t.Fatalf("form_builder.Bind() err = %v; want nil", err)
+				}
+				got := copySignup(signup)
+				gotErrors, err := got.Bind(tc.values, tc.opts...)
+				if err != nil {
+					t.Fatalf("Bind() err = %v; want nil", err)
+				}
+				if !reflect.DeepEqual(gotErrors, wantErrors) {
+					t.Errorf("Bind() errors = %v; want %v", gotErrors, wantErrors)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Bind() = %+v\nwant %+v", got, want)
+				}
+			})
+		}
+	}
+
+	var want, got Search
+	values := url.Values{"q": {"boots"}, "Open": {"on"}}
+	form_builder.Bind(values, &want, form_builder.WithDefaults())
+	got.Bind(values, form_builder.WithDefaults())
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Search.Bind() = %+v; want %+v", got, want)
+	}
+}
+
+// copySignup returns a deep copy of s, so binding into it doesn't change
+// the original.
+func copySignup(s Signup) Signup {
+	if s.Height != nil {
+		s.Height = height(*s.Height)
+	}
+	if s.Referrer != nil {
+		s.Referrer = text(*s.Referrer)
+	}
+	if s.Tags != nil {
+		s.Tags = append([]string{}, s.Tags...)
+	}
+	if s.Lucky != nil {
+		s.Lucky = append([]int{}, s.Lucky...)
+	}
+	return s
+}
+
+func TestValidate(t *testing.T) {
+	for name, signup := range signups {
+		t.Run(name, func(t *testing.T) {
+			signup := signup
+			want, err := form_builder.Validate(&signup)
+			if err != nil {
+				t.Fatalf("form_builder.Validate() err = %v; want nil", err)
+			}
+			if got := signup.Validate(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Validate() = %v\nwant %v", got, want)
+			}
+		})
+	}
+
+	search := Search{Base: Base{Page: -1}}
+	want, _ := form_builder.Validate(&search)
+	if got := search.Validate(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Search.Validate() = %v; want %v", got, want)
+	}
+}
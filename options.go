@@ -0,0 +1,144 @@
+package form_builder
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+)
+
+// Option customizes how Fields, HTML and NewForm resolve field descriptors.
+type Option func(*config)
+
+type config struct {
+	errors      []FieldError
+	tpl         *template.Template
+	idPrefix    string
+	defaults    bool
+	csrf        *CSRF
+	csrfReq     *http.Request
+	input       url.Values
+	translator  Translator
+	concurrency int
+	omitEmpty   bool
+	// disambiguate renames fields whose input name is taken rather than
+	// failing.
+	disambiguate bool
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{concurrency: 4}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithErrors attaches errors to the fields whose Name matches
+// FieldError.Field.
+func WithErrors(errors ...FieldError) Option {
+	return func(cfg *config) {
+		cfg.errors = append(cfg.errors, errors...)
+	}
+}
+
+// WithError attaches the field errors carried by err, as found by
+// AsFieldErrors, so errors returned from the domain layer can be passed
+// straight to a form. If err carries no field errors, its message is added
+// as a form-level error instead, so it should be meant for the user. A nil
+// err is ignored.
+func WithError(err error) Option {
+	return func(cfg *config) {
+		if err == nil {
+			return
+		}
+		if fes, ok := AsFieldErrors(err); ok {
+			cfg.errors = append(cfg.errors, fes...)
+			return
+		}
+		cfg.errors = append(cfg.errors, FieldError{Message: err.Error()})
+	}
+}
+
+// WithTemplate sets the template a Form uses to render each field. It has no
+// effect on Fields or HTML.
+func WithTemplate(t *template.Template) Option {
+	return func(cfg *config) {
+		cfg.tpl = t
+	}
+}
+
+// WithIDPrefix prepends prefix to every generated element ID, keeping IDs
+// unique when several forms share a page.
+func WithIDPrefix(prefix string) Option {
+	return func(cfg *config) {
+		cfg.idPrefix = prefix
+	}
+}
+
+// WithDefaults makes Bind set fields that are missing from the submitted
+// values from their default tag. Checkboxes are the exception: browsers
+// don't submit them when unchecked, so their default only applies when the
+// form is rendered.
+func WithDefaults() Option {
+	return func(cfg *config) {
+		cfg.defaults = true
+	}
+}
+
+// WithCSRF makes a Form render a hidden input holding a CSRF token for the
+// session r belongs to.
+func WithCSRF(c *CSRF, r *http.Request) Option {
+	return func(cfg *config) {
+		cfg.csrf = c
+		cfg.csrfReq = r
+	}
+}
+
+// WithInput sets the values submitted for a form, keyed by input name. Each
+// field's Value becomes the submitted value converted to the field's type,
+// or the raw submitted string if it can't be converted, so that invalid
+// input is shown back to the user exactly as they typed it. Password fields
+// are left empty, whatever their value.
+func WithInput(values url.Values) Option {
+	return func(cfg *config) {
+		cfg.input = values
+	}
+}
+
+// WithTranslator sets the Translator used to turn the Code of each
+// FieldError into a message, and to resolve label, placeholder and help
+// tags written as message keys, such as label=@user.email.
+func WithTranslator(tr Translator) Option {
+	return func(cfg *config) {
+		cfg.translator = tr
+	}
+}
+
+// WithConcurrency limits how many registered validators Validate runs at
+// once. It defaults to 4.
+func WithConcurrency(n int) Option {
+	return func(cfg *config) {
+		cfg.concurrency = n
+	}
+}
+
+// DisambiguateNames renames a field whose input name is already used by an
+// earlier field, rather than returning an error for it. The field is named
+// after its Go field path instead, such as "Billing.Email", with a number
+// appended if any field of the form has that name too, so fields are never
+// renamed to make way for it. The names of a form only agree between
+// rendering and binding if both are given this option.
+func DisambiguateNames() Option {
+	return func(cfg *config) {
+		cfg.disambiguate = true
+	}
+}
+
+// OmitEmpty makes Values leave out fields whose value would be encoded as
+// an empty string, and slices with no elements, to keep query strings
+// short.
+func OmitEmpty() Option {
+	return func(cfg *config) {
+		cfg.omitEmpty = true
+	}
+}
@@ -0,0 +1,317 @@
+package form_builder
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SchemaDraft is the JSON Schema dialect of the schemas returned by
+// JSONSchema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema describing a form struct, or one of its fields.
+// It marshals to JSON with encoding/json.
+type Schema struct {
+	Schema           string             `json:"$schema,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	Title            string             `json:"title,omitempty"`
+	Description      string             `json:"description,omitempty"`
+	Format           string             `json:"format,omitempty"`
+	ContentEncoding  string             `json:"contentEncoding,omitempty"`
+	ContentMediaType string             `json:"contentMediaType,omitempty"`
+	Default          interface{}        `json:"default,omitempty"`
+	Enum             []interface{}      `json:"enum,omitempty"`
+	MinLength        *int               `json:"minLength,omitempty"`
+	MaxLength        *int               `json:"maxLength,omitempty"`
+	Minimum          *float64           `json:"minimum,omitempty"`
+	Maximum          *float64           `json:"maximum,omitempty"`
+	MinItems         *int               `json:"minItems,omitempty"`
+	MaxItems         *int               `json:"maxItems,omitempty"`
+	Items            *Schema            `json:"items,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+}
+
+// formats maps input types to the JSON Schema formats of their values.
+var formats = map[string]string{
+	"email": "email",
+	"url":   "uri",
+	"date":  "date",
+	"time":  "time",
+}
+
+// JSONSchema returns a draft 2020-12 JSON Schema for strct, so clients that
+// build their own forms can share the struct behind a server-rendered one.
+//
+// Each field is described from the same metadata Fields uses: its label
+// becomes the title, its help text the description, its options an enum
+// and its default tag the default. The required, min, max and email rules
+// of its validate tag are turned into the matching keywords; registered
+// validators can't be described and are left out. Nested structs become
+// nested objects, and slices arrays. Properties are named after Go fields,
+// unless a name tag overrides them.
+func JSONSchema(strct interface{}, opts ...Option) (*Schema, error) {
+	cfg := newConfig(opts)
+
+	root := &Schema{Schema: SchemaDraft, Type: "object", Properties: make(map[string]*Schema)}
+	err := walkForm(strct, false, cfg, func(l leaf) error {
+		f, err := fieldOf(l, cfg)
+		if err != nil {
+			return err
+		}
+		s, err := fieldSchema(l, f)
+		if err != nil {
+			return err
+		}
+
+		parent := root
+		names, key := propertyPath(l)
+		for _, name := range names {
+			child, ok := parent.Properties[name]
+			if !ok {
+				child = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+				parent.Properties[name] = child
+			}
+			parent = child
+		}
+		parent.Properties[key] = s
+
+		rules, err := parseRules(l.tags["validate"])
+		if err != nil {
+			return fmt.Errorf("%v on field %s", err, l.field.Name)
+		}
+		for _, r := range rules {
+			if r.name == "required" {
+				parent.Required = append(parent.Required, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// UISchema returns a UI schema to go with the JSON Schema of strct, in the
+// format used by react-jsonschema-form. It holds the hints that JSON Schema
+// has no place for: the order of the fields, the widget for each input type
+// other than text, placeholders, the labels of options and the types of
+// file a file input accepts.
+func UISchema(strct interface{}, opts ...Option) (map[string]interface{}, error) {
+	cfg := newConfig(opts)
+
+	root := make(map[string]interface{})
+	err := walkForm(strct, false, cfg, func(l leaf) error {
+		f, err := fieldOf(l, cfg)
+		if err != nil {
+			return err
+		}
+
+		parent := root
+		names, key := propertyPath(l)
+		for _, name := range names {
+			child, ok := parent[name].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				addOrder(parent, name)
+				parent[name] = child
+			}
+			parent = child
+		}
+		addOrder(parent, key)
+
+		ui := make(map[string]interface{})
+		if f.Type != "text" && f.Type != "select" {
+			ui["ui:widget"] = f.Type
+		}
+		if _, ok := l.tags["placeholder"]; ok {
+			ui["ui:placeholder"] = f.Placeholder
+		}
+		for _, c := range f.Options {
+			if c.Label != c.Value {
+				var names []string
+				for _, c := range f.Options {
+					names = append(names, c.Label)
+				}
+				ui["ui:enumNames"] = names
+				break
+			}
+		}
+		if f.Accept != "" {
+			ui["ui:options"] = map[string]interface{}{"accept": f.Accept}
+		}
+		if len(ui) > 0 {
+			parent[key] = ui
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func addOrder(ui map[string]interface{}, key string) {
+	order, _ := ui["ui:order"].([]string)
+	ui["ui:order"] = append(order, key)
+}
+
+// propertyPath returns the names of the objects a leaf is nested in, and
+// the name of its own property.
+func propertyPath(l leaf) (names []string, key string) {
+	names = strings.Split(l.path, ".")
+	key = names[len(names)-1]
+	if v, ok := l.tags["name"]; ok {
+		key = v
+	}
+	return names[:len(names)-1], key
+}
+
+// fieldSchema describes a single leaf and its resolved field.
+func fieldSchema(l leaf, f Field) (*Schema, error) {
+	s, err := typeSchema(l.value.Type())
+	if err != nil {
+		return nil, fmt.Errorf("%v on field %s", err, l.field.Name)
+	}
+	s.Title = f.Label
+	s.Description = f.Help
+
+	// Keywords that describe a single value go on the items of arrays.
+	value := s
+	if s.Type == "array" {
+		value = s.Items
+	}
+	value.Format = formats[f.Type]
+	if f.Accept != "" && !strings.ContainsAny(f.Accept, ",*") && !strings.HasPrefix(f.Accept, ".") {
+		value.ContentMediaType = f.Accept
+	}
+
+	for _, c := range f.Options {
+		v, err := convert(elemType(l.value.Type()), c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("form: invalid option %q on field %s: %v", c.Value, l.field.Name, err)
+		}
+		value.Enum = append(value.Enum, reflect.Indirect(reflect.ValueOf(v)).Interface())
+	}
+
+	if def, ok := l.tags["default"]; ok {
+		v, err := convert(l.value.Type(), def)
+		if err != nil {
+			return nil, fmt.Errorf("form: invalid default %q on field %s: %v", def, l.field.Name, err)
+		}
+		s.Default = reflect.Indirect(reflect.ValueOf(v)).Interface()
+	}
+
+	rules, err := parseRules(l.tags["validate"])
+	if err != nil {
+		return nil, fmt.Errorf("%v on field %s", err, l.field.Name)
+	}
+	required := false
+	for _, r := range rules {
+		switch r.name {
+		case "required":
+			required = true
+		case "min", "max":
+			err = s.bound(r.name, r.arg)
+		case "email":
+			value.Format = "email"
+		}
+		if err != nil {
+			return nil, fmt.Errorf("form: invalid rule %q on field %s: %v", r.name, l.field.Name, err)
+		}
+	}
+	if required {
+		s.nonEmpty()
+	}
+	return s, nil
+}
+
+// nonEmpty rules out the empty strings and arrays that a required rule
+// rejects, on top of the property being listed as required, even if a min
+// rule would allow them.
+func (s *Schema) nonEmpty() {
+	one := 1
+	switch s.Type {
+	case "string":
+		if s.MinLength == nil || *s.MinLength < one {
+			s.MinLength = &one
+		}
+	case "array":
+		if s.MinItems == nil || *s.MinItems < one {
+			s.MinItems = &one
+		}
+	}
+}
+
+// bound sets the keyword for a min or max rule, which depends on the type
+// being limited, just as it does for Validate.
+func (s *Schema) bound(rule, arg string) error {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", arg)
+	}
+	size := int(n)
+
+	switch s.Type {
+	case "string":
+		if rule == "min" {
+			s.MinLength = &size
+		} else {
+			s.MaxLength = &size
+		}
+	case "array":
+		if rule == "min" {
+			s.MinItems = &size
+		} else {
+			s.MaxItems = &size
+		}
+	case "integer", "number":
+		if rule == "min" {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	default:
+		return fmt.Errorf("can't be applied to %s", s.Type)
+	}
+	return nil
+}
+
+// typeSchema returns the schema for values of type t.
+func typeSchema(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Ptr && !isFile(t) {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == fileHeaderType:
+		return &Schema{Type: "string", ContentEncoding: "base64"}, nil
+	case reflect.PtrTo(t).Implements(textUnmarshalerType):
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var zero float64
+		return &Schema{Type: "integer", Minimum: &zero}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice:
+		items, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	default:
+		return nil, fmt.Errorf("form: can't describe a field of type %s", t)
+	}
+}
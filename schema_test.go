@@ -0,0 +1,94 @@
+package form_builder_test
+
+import (
+	"encoding/json"
+	"form_builder"
+	"mime/multipart"
+	"os"
+	"strings"
+	"testing"
+)
+
+type profile struct {
+	Name     string   `form:"label=Full name;help=As it appears on your passport;validate=required,max:50"`
+	Email    string   `form:"name=email;type=email;placeholder=you@example.com;validate=required"`
+	Age      uint8    `form:"validate=min:18"`
+	Score    float64  `form:"default=2.5;validate=max:10"`
+	Role     string   `form:"options=admin:Administrator,editor:Editor;default=editor"`
+	Tags     []string `form:"options=go,web;validate=required,max:2"`
+	Bio      *string  `form:"type=textarea"`
+	Avatar   *multipart.FileHeader
+	Contract *multipart.FileHeader `form:"accept=application/pdf"`
+	Address  struct {
+		Street string `form:"validate=required"`
+		Zip    int    `form:"name=postcode"`
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	tests := map[string]struct {
+		schema func() (interface{}, error)
+		want   string
+	}{
+		"JSON Schema": {
+			schema: func() (interface{}, error) { return form_builder.JSONSchema(profile{}) },
+			want:   "TestJSONSchema.golden",
+		},
+		"UI schema": {
+			schema: func() (interface{}, error) { return form_builder.UISchema(&profile{}) },
+			want:   "TestUISchema.golden",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, err := tc.schema()
+			if err != nil {
+				t.Fatalf("schema err = %v; want nil", err)
+			}
+			got, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				t.Fatalf("json.MarshalIndent() err = %v; want nil", err)
+			}
+
+			gotFilename := strings.Replace(tc.want, ".golden", ".got", 1)
+			os.Remove(gotFilename)
+
+			if updateFlag {
+				writeFile(t, tc.want, string(got))
+				t.Logf("Updated golden file %s", tc.want)
+			}
+
+			if string(got) != string(readFile(t, tc.want)) {
+				t.Errorf("schema - results do not match golden file.")
+				writeFile(t, gotFilename, string(got))
+				t.Errorf(" To compare run: diff %s %s", gotFilename, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchema_errors(t *testing.T) {
+	tests := map[string]interface{}{
+		"Not a struct": "profile",
+		"Invalid option": struct {
+			Size int `form:"options=small,large"`
+		}{},
+		"Invalid default": struct {
+			Size int `form:"default=large"`
+		}{},
+		"Invalid rule": struct {
+			Agree bool `form:"validate=min:1"`
+		}{},
+		"Unknown type": struct{ Meta map[string]string }{},
+	}
+
+	for name, strct := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := form_builder.JSONSchema(strct)
+			if err == nil {
+				t.Errorf("JSONSchema() err = nil; want an error")
+			}
+		})
+	}
+}
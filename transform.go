@@ -0,0 +1,93 @@
+package form_builder
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// TransformFunc normalizes a submitted value before it is bound.
+type TransformFunc func(string) string
+
+var transforms = struct {
+	sync.RWMutex
+	m map[string]TransformFunc
+}{m: map[string]TransformFunc{
+	"trim":          strings.TrimSpace,
+	"collapse":      collapseSpace,
+	"lower":         strings.ToLower,
+	"upper":         strings.ToUpper,
+	"nfc":           norm.NFC.String,
+	"strip_control": stripControl,
+}}
+
+// RegisterTransform makes fn available as a transform called name in
+// transform tags, alongside the built-in trim, collapse, lower, upper, nfc
+// and strip_control.
+//
+// RegisterTransform is meant to be called from init functions, and panics
+// if name is already taken.
+func RegisterTransform(name string, fn TransformFunc) {
+	transforms.Lock()
+	defer transforms.Unlock()
+
+	if _, ok := transforms.m[name]; ok {
+		panic(fmt.Sprintf("form: transform %q is already registered", name))
+	}
+	transforms.m[name] = fn
+}
+
+// parseTransforms returns the transforms listed in a transform tag, such as
+// "trim,lower", in the order they should be applied.
+func parseTransforms(tag string) ([]TransformFunc, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	transforms.RLock()
+	defer transforms.RUnlock()
+
+	var fns []TransformFunc
+	for _, name := range strings.Split(tag, ",") {
+		fn, ok := transforms.m[name]
+		if !ok {
+			return nil, fmt.Errorf("form: unknown transform %q", name)
+		}
+		fns = append(fns, fn)
+	}
+	return fns, nil
+}
+
+func applyTransforms(fns []TransformFunc, raws []string) []string {
+	if len(fns) == 0 {
+		return raws
+	}
+	out := make([]string, len(raws))
+	for i, raw := range raws {
+		for _, fn := range fns {
+			raw = fn(raw)
+		}
+		out[i] = raw
+	}
+	return out
+}
+
+// collapseSpace trims s and replaces every run of whitespace inside it with
+// a single space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// stripControl removes control characters, such as NUL or escape, from s.
+// Tabs and newlines are kept, since textareas legitimately contain them.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,74 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func init() {
+	form_builder.RegisterTransform("test_digits", func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < '0' || r > '9' {
+				return -1
+			}
+			return r
+		}, s)
+	})
+}
+
+func TestBind_transforms(t *testing.T) {
+	var got struct {
+		Email string   `form:"transform=trim,lower"`
+		Name  string   `form:"transform=collapse"`
+		Code  string   `form:"transform=upper"`
+		Cafe  string   `form:"transform=nfc"`
+		Note  string   `form:"transform=strip_control"`
+		Phone int      `form:"transform=test_digits;validate=required"`
+		Tags  []string `form:"transform=trim"`
+	}
+
+	errors, err := form_builder.Bind(url.Values{
+		"Email": {"  Alice@CC.cc \n"},
+		"Name":  {" Alice \t  Smith "},
+		"Code":  {"abc"},
+		"Cafe":  {"cafe\u0301"},
+		"Note":  {"a\x00b\x1bc\td\ne"},
+		"Phone": {"(555) 123-4567"},
+		"Tags":  {" a ", "b "},
+	}, &got)
+	if err != nil {
+		t.Fatalf("Bind() err = %v; want nil", err)
+	}
+	if len(errors) != 0 {
+		t.Fatalf("Bind() errors = %v; want none", errors)
+	}
+
+	tests := map[string]struct {
+		got, want interface{}
+	}{
+		"trim,lower":    {got.Email, "alice@cc.cc"},
+		"collapse":      {got.Name, "Alice Smith"},
+		"upper":         {got.Code, "ABC"},
+		"nfc":           {got.Cafe, "caf\u00e9"},
+		"strip_control": {got.Note, "abc\td\ne"},
+		"registered":    {got.Phone, 5551234567},
+		"slices":        {strings.Join(got.Tags, "|"), "a|b"},
+	}
+	for name, tc := range tests {
+		if tc.got != tc.want {
+			t.Errorf("%s: got %q; want %q", name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestBind_unknownTransform(t *testing.T) {
+	var dst struct {
+		Name string `form:"transform=nope"`
+	}
+	_, err := form_builder.Bind(url.Values{}, &dst)
+	if err == nil {
+		t.Errorf("Bind() err = nil; want an error")
+	}
+}
@@ -0,0 +1,344 @@
+package form_builder
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// rule is a single entry of a validate tag, such as "required" or "min:3".
+type rule struct {
+	name string
+	arg  string
+}
+
+// check returns the error code for a value that breaks a rule, along with
+// any parameters needed to describe it, or "" if the value satisfies the
+// rule. It returns an error if the rule itself is misused.
+type check func(v reflect.Value, arg string) (code string, params map[string]interface{}, err error)
+
+var builtinRules = map[string]check{
+	"required": checkRequired,
+	"min":      checkMin,
+	"max":      checkMax,
+	"email":    checkEmail,
+}
+
+// FormValidator is implemented by structs with rules that span several
+// fields, such as a password confirmation that must match the password.
+// Validate calls ValidateForm on the struct it is given and on every nested
+// struct.
+//
+// The Field of each returned error may be the input name or the Go field
+// name of a field in the struct. Errors returned by a nested struct are
+// resolved relative to it, so a nested Address struct can return an error
+// for "Zip" and it will be reported for "Address.Zip". An empty Field is a
+// form-level error.
+type FormValidator interface {
+	ValidateForm() []FieldError
+}
+
+// ContextFormValidator is like FormValidator, but is given the context
+// passed to ValidateContext, for rules that need to look something up.
+type ContextFormValidator interface {
+	ValidateFormContext(ctx context.Context) []FieldError
+}
+
+// Validate checks strct, a struct or a pointer to one, against the rules in
+// its validate tags and returns an error for each rule a field breaks. Rules
+// are separated by commas and some take an argument after a colon:
+//
+//	Name  string `form:"validate=required,max:50"`
+//	Email string `form:"validate=required,email"`
+//	Age   int    `form:"validate=min:18"`
+//
+// The supported rules are required, min and max (the length of strings and
+// slices, or the value of numbers) and email. Only required applies to
+// empty values. Each error has a Code naming the broken rule, such as
+// "required" or "too_short", and its Params include the field's label.
+//
+// Rules can also name validators added with RegisterValidator. These are
+// run concurrently, up to the limit set by WithConcurrency, but their
+// errors are still returned in the order of the fields and rules.
+//
+// Fields with an options tag must hold one of its values, or an error with
+// the Code "invalid_option" is returned.
+//
+// The errors returned by any FormValidator or ContextFormValidator in strct
+// follow the errors for its tags. The error is only non-nil if a tag is
+// malformed, if a registered validator fails with an error that isn't a
+// FieldErrorer, or if the context of ValidateContext is done before every
+// validator has finished.
+func Validate(strct interface{}, opts ...Option) (FieldErrors, error) {
+	return ValidateContext(context.Background(), strct, opts...)
+}
+
+// ValidateContext is like Validate, but passes ctx on to any registered
+// validator and ContextFormValidator.
+func ValidateContext(ctx context.Context, strct interface{}, opts ...Option) (FieldErrors, error) {
+	cfg := newConfig(opts)
+
+	// Every rule that fails gets a slot for its errors, so they come out in
+	// field and rule order however long registered validators take.
+	var slots [][]FieldError
+	var jobs []validatorJob
+	var leaves []leaf
+	err := walkForm(strct, false, cfg, func(l leaf) error {
+		leaves = append(leaves, l)
+
+		rules, err := parseRules(l.tags["validate"])
+		if err != nil {
+			return fmt.Errorf("%v on field %s", err, l.field.Name)
+		}
+
+		value := valueOf(l.value)
+		for _, r := range rules {
+			if r.name != "required" && value.IsZero() {
+				continue
+			}
+			if fn, ok := lookupValidator(r.name); ok {
+				jobs = append(jobs, validatorJob{fn: fn, value: value.Interface(), leaf: l, slot: len(slots)})
+				slots = append(slots, nil)
+				continue
+			}
+			code, params, err := builtinRules[r.name](value, r.arg)
+			if err != nil {
+				return fmt.Errorf("form: invalid rule %q on field %s: %v", r.name, l.field.Name, err)
+			}
+			if code == "" {
+				continue
+			}
+			if params == nil {
+				params = make(map[string]interface{})
+			}
+			params["label"] = labelOf(l)
+			slots = append(slots, []FieldError{{
+				Field:  l.name,
+				Code:   code,
+				Params: params,
+			}})
+		}
+
+		if choices, ok := choicesOf(l); ok && !value.IsZero() && !isChoice(value, choices) {
+			slots = append(slots, []FieldError{{
+				Field:  l.name,
+				Code:   "invalid_option",
+				Params: map[string]interface{}{"label": labelOf(l)},
+			}})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = runValidators(ctx, jobs, slots, cfg.concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var ferrs FieldErrors
+	for _, slot := range slots {
+		ferrs = append(ferrs, slot...)
+	}
+	var defs []FieldDefinition
+	switch strct := strct.(type) {
+	case *Definition:
+		defs = strct.Fields
+	case *Data:
+		defs = strct.def.Fields
+	default:
+		ferrs = append(ferrs, validateStructs(ctx, valueOf(strct), nil, leaves)...)
+	}
+	walkStructs(defs, nil, func(v reflect.Value, names []string) {
+		ferrs = append(ferrs, validateStructs(ctx, v, names, leaves)...)
+	})
+	return ferrs, nil
+}
+
+// validateStructs calls the FormValidator and ContextFormValidator methods
+// of v and every struct nested in it, resolving the fields of their errors
+// relative to the struct they came from.
+func validateStructs(ctx context.Context, v reflect.Value, names []string, leaves []leaf) []FieldError {
+	// Copy v if it isn't addressable, so methods with pointer receivers
+	// can still be called.
+	ptr := v
+	if v.CanAddr() {
+		ptr = v.Addr()
+	} else {
+		ptr = reflect.New(v.Type())
+		ptr.Elem().Set(v)
+	}
+
+	var found []FieldError
+	if validator, ok := ptr.Interface().(FormValidator); ok {
+		found = append(found, validator.ValidateForm()...)
+	}
+	if validator, ok := ptr.Interface().(ContextFormValidator); ok {
+		found = append(found, validator.ValidateFormContext(ctx)...)
+	}
+
+	var ferrs []FieldError
+	for _, ferr := range found {
+		ferr.Field = resolveField(ferr.Field, names, leaves)
+		ferrs = append(ferrs, ferr)
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		fv := v.Field(i)
+		if !fv.CanInterface() || !isStruct(sf.Type) {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			continue
+		}
+		nested := append(names[:len(names):len(names)], sf.Name)
+		ferrs = append(ferrs, validateStructs(ctx, valueOf(fv), nested, leaves)...)
+	}
+	return ferrs
+}
+
+// resolveField returns the input name for field, as named by a struct
+// nested at the Go field path names. field may be the Go field name of one
+// of the struct's fields, or the input name of one. Anything else is
+// assumed to be relative to the struct and prefixed with its path.
+func resolveField(field string, names []string, leaves []leaf) string {
+	if field == "" {
+		return ""
+	}
+
+	prefix := strings.Join(names, ".")
+	path := field
+	if prefix != "" {
+		path = prefix + "." + field
+	}
+
+	for _, l := range leaves {
+		if l.path == path {
+			return l.name
+		}
+	}
+	for _, l := range leaves {
+		if l.name == field && (prefix == "" || strings.HasPrefix(l.path, prefix+".")) {
+			return l.name
+		}
+	}
+	return path
+}
+
+// isChoice reports whether v, or every element of v if it is a slice, is
+// the value of one of choices.
+func isChoice(v reflect.Value, choices []Choice) bool {
+	raws, err := formatValues(v)
+	if err != nil {
+		return false
+	}
+	for _, raw := range raws {
+		found := false
+		for _, c := range choices {
+			if c.Value == raw {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRules(tag string) ([]rule, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	var rules []rule
+	for _, s := range strings.Split(tag, ",") {
+		r := rule{name: s}
+		if i := strings.Index(s, ":"); i >= 0 {
+			r.name, r.arg = s[:i], s[i+1:]
+		}
+		if _, ok := builtinRules[r.name]; !ok {
+			if _, ok := lookupValidator(r.name); !ok {
+				return nil, fmt.Errorf("form: unknown validation rule %q", r.name)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func labelOf(l leaf) string {
+	if v, ok := l.tags["label"]; ok {
+		return v
+	}
+	return l.field.Name
+}
+
+func checkRequired(v reflect.Value, _ string) (string, map[string]interface{}, error) {
+	if v.IsZero() {
+		return "required", nil, nil
+	}
+	return "", nil, nil
+}
+
+func checkMin(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+	bound, n, kind, err := measure(v, arg)
+	if err != nil || n >= bound {
+		return "", nil, err
+	}
+	code := map[string]string{"string": "too_short", "list": "too_few", "number": "too_small"}[kind]
+	return code, map[string]interface{}{"min": bound}, nil
+}
+
+func checkMax(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+	bound, n, kind, err := measure(v, arg)
+	if err != nil || n <= bound {
+		return "", nil, err
+	}
+	code := map[string]string{"string": "too_long", "list": "too_many", "number": "too_large"}[kind]
+	return code, map[string]interface{}{"max": bound}, nil
+}
+
+// measure parses the bound of a min or max rule and returns the size of v
+// to compare it against: the length of strings and lists, or the value of
+// numbers.
+func measure(v reflect.Value, arg string) (bound, n float64, kind string, err error) {
+	bound, err = strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("%q is not a number", arg)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return bound, float64(utf8.RuneCountInString(v.String())), "string", nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return bound, float64(v.Len()), "list", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return bound, float64(v.Int()), "number", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return bound, float64(v.Uint()), "number", nil
+	case reflect.Float32, reflect.Float64:
+		return bound, v.Float(), "number", nil
+	default:
+		return 0, 0, "", fmt.Errorf("can't be applied to %s", v.Type())
+	}
+}
+
+func checkEmail(v reflect.Value, _ string) (string, map[string]interface{}, error) {
+	if v.Kind() != reflect.String {
+		return "", nil, fmt.Errorf("can't be applied to %s", v.Type())
+	}
+	addr, err := mail.ParseAddress(v.String())
+	if err != nil || addr.Address != v.String() {
+		return "invalid_email", nil, nil
+	}
+	return "", nil, nil
+}
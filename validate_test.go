@@ -0,0 +1,200 @@
+package form_builder_test
+
+import (
+	"context"
+	"form_builder"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	type account struct {
+		Name     string   `form:"validate=required,max:10"`
+		Email    string   `form:"name=email;label=Email address;validate=required,email"`
+		Password string   `form:"validate=min:8"`
+		Age      int      `form:"validate=min:18,max:130"`
+		Tags     []string `form:"validate=max:2"`
+		Nick     *string  `form:"validate=required"`
+	}
+
+	nick := "ally"
+
+	tests := map[string]struct {
+		strct interface{}
+		want  form_builder.FieldErrors
+	}{
+		"Valid": {
+			strct: account{
+				Name:  "Alice",
+				Email: "alice@cc.cc",
+				Age:   25,
+				Nick:  &nick,
+			},
+		},
+		"Empty values only break required": {
+			strct: &account{},
+			want: form_builder.FieldErrors{
+				{Field: "Name", Code: "required", Params: map[string]interface{}{"label": "Name"}},
+				{Field: "email", Code: "required", Params: map[string]interface{}{"label": "Email address"}},
+				{Field: "Nick", Code: "required", Params: map[string]interface{}{"label": "Nick"}},
+			},
+		},
+		"Every rule can fail": {
+			strct: account{
+				Name:     "Alice Smith Jr",
+				Email:    "Alice <alice@cc.cc>",
+				Password: "secret",
+				Age:      12,
+				Tags:     []string{"a", "b", "c"},
+				Nick:     &nick,
+			},
+			want: form_builder.FieldErrors{
+				{Field: "Name", Code: "too_long", Params: map[string]interface{}{"label": "Name", "max": 10.0}},
+				{Field: "email", Code: "invalid_email", Params: map[string]interface{}{"label": "Email address"}},
+				{Field: "Password", Code: "too_short", Params: map[string]interface{}{"label": "Password", "min": 8.0}},
+				{Field: "Age", Code: "too_small", Params: map[string]interface{}{"label": "Age", "min": 18.0}},
+				{Field: "Tags", Code: "too_many", Params: map[string]interface{}{"label": "Tags", "max": 2.0}},
+			},
+		},
+		"Options must be chosen from": {
+			strct: struct {
+				Status string   `form:"options=draft:Draft,live:Published"`
+				Tags   []string `form:"options=go,web"`
+				Size   int      `form:"options=1,2,3"`
+			}{
+				Status: "Published",
+				Tags:   []string{"go", "rust"},
+				Size:   2,
+			},
+			want: form_builder.FieldErrors{
+				{Field: "Status", Code: "invalid_option", Params: map[string]interface{}{"label": "Status"}},
+				{Field: "Tags", Code: "invalid_option", Params: map[string]interface{}{"label": "Tags"}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := form_builder.Validate(tc.strct)
+			if err != nil {
+				t.Fatalf("Validate() err = %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Validate():\n  got %v;\n want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidate_invalidRules(t *testing.T) {
+	tests := map[string]interface{}{
+		"not a struct": 123,
+		"unknown rule": struct {
+			Name string `form:"validate=unknown"`
+		}{Name: "x"},
+		"invalid argument": struct {
+			Name string `form:"validate=min:three"`
+		}{Name: "x"},
+		"wrong type": struct {
+			Agree bool `form:"validate=min:1"`
+		}{Agree: true},
+	}
+
+	for name, strct := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := form_builder.Validate(strct)
+			if err == nil {
+				t.Errorf("Validate() err = nil; want an error")
+			}
+		})
+	}
+}
+
+type passwordChange struct {
+	Password        string `form:"validate=required"`
+	ConfirmPassword string `form:"name=confirm"`
+	Period          period
+	Billing         *address
+}
+
+func (p passwordChange) ValidateForm() []form_builder.FieldError {
+	if p.Password != p.ConfirmPassword {
+		return []form_builder.FieldError{{Field: "ConfirmPassword", Message: "Passwords must match"}}
+	}
+	return nil
+}
+
+type period struct {
+	Start int
+	End   int
+}
+
+func (p *period) ValidateForm() []form_builder.FieldError {
+	if p.End < p.Start {
+		return []form_builder.FieldError{{Field: "End", Message: "End must be after start"}}
+	}
+	return nil
+}
+
+type address struct {
+	Zip string `form:"name=zip"`
+}
+
+type ctxKey struct{}
+
+func (a address) ValidateFormContext(ctx context.Context) []form_builder.FieldError {
+	if a.Zip == ctx.Value(ctxKey{}) {
+		return []form_builder.FieldError{
+			{Field: "zip", Message: "We don't deliver there"},
+			{Field: "Country", Message: "Unknown country"},
+			{Message: "Please call us"},
+		}
+	}
+	return nil
+}
+
+func TestValidateContext_formValidators(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "99999")
+
+	tests := map[string]struct {
+		strct interface{}
+		want  form_builder.FieldErrors
+	}{
+		"Valid": {
+			strct: passwordChange{Password: "secret", ConfirmPassword: "secret", Period: period{1, 2}},
+		},
+		"Struct-level errors follow tag errors": {
+			strct: passwordChange{ConfirmPassword: "secret"},
+			want: form_builder.FieldErrors{
+				{Field: "Password", Code: "required", Params: map[string]interface{}{"label": "Password"}},
+				{Field: "confirm", Message: "Passwords must match"},
+			},
+		},
+		"Nested errors are resolved relative to their struct": {
+			strct: &passwordChange{
+				Password:        "secret",
+				ConfirmPassword: "secret",
+				Period:          period{2, 1},
+				Billing:         &address{Zip: "99999"},
+			},
+			want: form_builder.FieldErrors{
+				{Field: "Period.End", Message: "End must be after start"},
+				{Field: "zip", Message: "We don't deliver there"},
+				{Field: "Billing.Country", Message: "Unknown country"},
+				{Message: "Please call us"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := form_builder.ValidateContext(ctx, tc.strct)
+			if err != nil {
+				t.Fatalf("ValidateContext() err = %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ValidateContext():\n  got %v;\n want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package form_builder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ValidatorFunc checks the value of a single field, such as whether an
+// email address is already taken. It returns nil if the value is valid.
+//
+// An invalid value is reported by returning a FieldError, or any error that
+// implements FieldErrorer, whose errors are shown on the field. Any other
+// error, such as a database that can't be reached, means the value couldn't
+// be checked at all, and fails the whole validation with that error rather
+// than being shown to the user.
+type ValidatorFunc func(ctx context.Context, value interface{}) error
+
+var validators = struct {
+	sync.RWMutex
+	m map[string]ValidatorFunc
+}{m: make(map[string]ValidatorFunc)}
+
+// RegisterValidator makes fn available as a rule called name in validate
+// tags, as in validate=required,unique_email. Like the built-in rules other
+// than required, it is only run for fields that aren't empty.
+//
+// RegisterValidator is meant to be called from init functions, and panics
+// if name is already taken. Forms compiled by Compile must be compiled after
+// the validators they use are registered, which package-level variables are
+// not when they are in the same package as the init function. See Compile.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validators.Lock()
+	defer validators.Unlock()
+
+	if _, ok := builtinRules[name]; ok {
+		panic(fmt.Sprintf("form: validation rule %q is built in", name))
+	}
+	if _, ok := validators.m[name]; ok {
+		panic(fmt.Sprintf("form: validator %q is already registered", name))
+	}
+	validators.m[name] = fn
+}
+
+func lookupValidator(name string) (ValidatorFunc, bool) {
+	validators.RLock()
+	defer validators.RUnlock()
+	fn, ok := validators.m[name]
+	return fn, ok
+}
+
+// validatorJob is a registered validator waiting to be run on a field.
+type validatorJob struct {
+	fn    ValidatorFunc
+	value interface{}
+	leaf  leaf
+	// slot is where the job's errors go, keeping them in field and rule
+	// order however long each job takes.
+	slot int
+}
+
+// runValidators runs jobs concurrently, no more than limit at a time, and
+// stores the errors each one finds in its slot. It stops starting jobs once
+// ctx is done and returns ctx's error, and otherwise returns the first error
+// of a job that isn't a FieldErrorer.
+func runValidators(ctx context.Context, jobs []validatorJob, slots [][]FieldError, limit int) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, limit)
+		errs = make([]error, len(jobs))
+	)
+loop:
+	for i, job := range jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+
+		wg.Add(1)
+		go func(i int, job validatorJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = job.fn(ctx, job.value)
+		}(i, job)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, job := range jobs {
+		if errs[i] == nil {
+			continue
+		}
+		fes, ok := AsFieldErrors(errs[i])
+		if !ok {
+			return fmt.Errorf("form: validating field %s: %w", job.leaf.name, errs[i])
+		}
+		slots[job.slot] = validatorErrors(job.leaf, fes)
+	}
+	return nil
+}
+
+// validatorErrors turns the field errors returned by a ValidatorFunc into
+// errors for the field l.
+func validatorErrors(l leaf, fes FieldErrors) []FieldError {
+	ferrs := make([]FieldError, len(fes))
+	for i, fe := range fes {
+		if fe.Field == "" {
+			fe.Field = l.name
+		}
+		if fe.Code != "" {
+			params := map[string]interface{}{"label": labelOf(l)}
+			for k, v := range fe.Params {
+				params[k] = v
+			}
+			fe.Params = params
+		}
+		ferrs[i] = fe
+	}
+	return ferrs
+}
@@ -0,0 +1,129 @@
+package form_builder_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"form_builder"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	inFlight    int
+	maxInFlight int
+	inFlightMu  sync.Mutex
+)
+
+func init() {
+	form_builder.RegisterValidator("test_unique_email", func(ctx context.Context, value interface{}) error {
+		if value == "taken@cc.cc" {
+			return form_builder.FieldError{Message: "Email address is already taken"}
+		}
+		return nil
+	})
+	form_builder.RegisterValidator("test_timeout", func(ctx context.Context, value interface{}) error {
+		return fmt.Errorf("db: %w", context.DeadlineExceeded)
+	})
+	form_builder.RegisterValidator("test_slow", func(ctx context.Context, value interface{}) error {
+		inFlightMu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		inFlightMu.Unlock()
+		defer func() {
+			inFlightMu.Lock()
+			inFlight--
+			inFlightMu.Unlock()
+		}()
+
+		// Finish in the reverse of field order, to prove the errors are
+		// still returned in field order.
+		n := value.(int)
+		select {
+		case <-time.After(time.Duration(10-n) * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return form_builder.FieldError{Code: "too_slow", Params: map[string]interface{}{"n": n}}
+	})
+}
+
+func TestValidate_registered(t *testing.T) {
+	strct := struct {
+		Email string `form:"validate=required,test_unique_email"`
+		Other string `form:"validate=test_unique_email"`
+		A     int    `form:"validate=test_slow,max:0"`
+		B     int    `form:"validate=test_slow"`
+		C     int    `form:"validate=test_slow"`
+		D     int    `form:"validate=test_slow"`
+	}{
+		Email: "taken@cc.cc",
+		A:     1, B: 2, C: 3, D: 4,
+	}
+
+	maxInFlight = 0
+	got, err := form_builder.Validate(strct, form_builder.WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("Validate() err = %v; want nil", err)
+	}
+
+	want := form_builder.FieldErrors{
+		{Field: "Email", Message: "Email address is already taken"},
+		{Field: "A", Code: "too_slow", Params: map[string]interface{}{"label": "A", "n": 1}},
+		{Field: "A", Code: "too_large", Params: map[string]interface{}{"label": "A", "max": 0.0}},
+		{Field: "B", Code: "too_slow", Params: map[string]interface{}{"label": "B", "n": 2}},
+		{Field: "C", Code: "too_slow", Params: map[string]interface{}{"label": "C", "n": 3}},
+		{Field: "D", Code: "too_slow", Params: map[string]interface{}{"label": "D", "n": 4}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate():\n  got %v;\n want %v", got, want)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("ran %d validators at once; want at most 2", maxInFlight)
+	}
+}
+
+func TestValidateContext_canceled(t *testing.T) {
+	strct := struct {
+		A int `form:"validate=test_slow"`
+	}{A: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := form_builder.ValidateContext(ctx, strct)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ValidateContext() err = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestValidate_validatorFailure(t *testing.T) {
+	strct := struct {
+		Email string `form:"validate=test_timeout"`
+	}{Email: "a@cc.cc"}
+
+	got, err := form_builder.Validate(strct)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Validate() err = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if got != nil {
+		t.Errorf("Validate() = %v; want no field errors", got)
+	}
+}
+
+func TestRegisterValidator_duplicate(t *testing.T) {
+	for _, name := range []string{"test_unique_email", "required"} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Errorf("RegisterValidator(%q) did not panic", name)
+				}
+			}()
+			form_builder.RegisterValidator(name, func(context.Context, interface{}) error { return nil })
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package form_builder
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// Values encodes strct into the values a browser would submit from the form
+// HTML renders for it, keyed by input name. It is meant for GET forms, such
+// as search and filter forms, whose query strings need to be built for
+// links.
+//
+// Fields are named and skipped exactly as they are by Fields, and fields
+// with a zero value fall back to their default tag. Slices are encoded as
+// one value per element under a repeated key, and file fields are left
+// out. Values are formatted so that Bind turns them back into the same
+// struct: types implementing encoding.TextMarshaler are marshaled, and other
+// zero values are encoded as empty strings, just as they are rendered with
+// no value. Use OmitEmpty to leave empty values out altogether.
+func Values(strct interface{}, opts ...Option) (url.Values, error) {
+	cfg := newConfig(opts)
+
+	values := make(url.Values)
+	err := walkForm(strct, false, cfg, func(l leaf) error {
+		if isFile(l.value.Type()) {
+			return nil
+		}
+
+		v := l.value
+		if def, ok := l.tags["default"]; ok && valueOf(v).IsZero() {
+			value, err := convert(v.Type(), def)
+			if err != nil {
+				return fmt.Errorf("form: invalid default %q on field %s: %v", def, l.field.Name, err)
+			}
+			v = reflect.ValueOf(value)
+		}
+
+		raws, err := formatValues(v)
+		if err != nil {
+			return err
+		}
+		if len(raws) == 0 || cfg.omitEmpty && isEmpty(raws) {
+			return nil
+		}
+		values[l.name] = append(values[l.name], raws...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// isEmpty reports whether raws holds no values, or only empty ones.
+func isEmpty(raws []string) bool {
+	for _, raw := range raws {
+		if raw != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// formatValues is the reverse of setValues: slices are formatted as one
+// value per element, and any other type as a single value.
+func formatValues(v reflect.Value) ([]string, error) {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 && !isTextMarshaler(v) {
+		raws := make([]string, v.Len())
+		for i := range raws {
+			raw, err := formatValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			raws[i] = raw
+		}
+		return raws, nil
+	}
+
+	if valueOf(v).IsZero() {
+		return []string{""}, nil
+	}
+	raw, err := formatValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{raw}, nil
+}
+
+// formatValue is the reverse of setValue.
+func formatValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		return formatValue(v.Elem())
+	}
+
+	if isTextMarshaler(v) {
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			m = v.Addr().Interface().(encoding.TextMarshaler)
+		}
+		text, err := m.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("form: can't encode a field of type %s", v.Type())
+	}
+}
+
+func isTextMarshaler(v reflect.Value) bool {
+	marshaler := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	return v.Type().Implements(marshaler) || v.CanAddr() && v.Addr().Type().Implements(marshaler)
+}
@@ -0,0 +1,125 @@
+package form_builder_test
+
+import (
+	"form_builder"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type search struct {
+	Query    string `form:"name=q"`
+	Page     int    `form:"default=1"`
+	Sort     string `form:"default=newest"`
+	Tags     []string
+	Open     bool
+	MinPrice *float64
+	Server   net.IP
+	Filter   struct {
+		Owner string
+	}
+}
+
+func TestValues(t *testing.T) {
+	price := 9.99
+
+	tests := map[string]struct {
+		strct interface{}
+		opts  []form_builder.Option
+		want  url.Values
+	}{
+		"Values are formatted and named like the rendered inputs": {
+			strct: search{
+				Query:    "red shoes",
+				Page:     3,
+				Sort:     "price",
+				Tags:     []string{"sale", "new"},
+				Open:     true,
+				MinPrice: &price,
+				Server:   net.IPv4(10, 0, 0, 1),
+				Filter:   struct{ Owner string }{"bob"},
+			},
+			want: url.Values{
+				"q":            {"red shoes"},
+				"Page":         {"3"},
+				"Sort":         {"price"},
+				"Tags":         {"sale", "new"},
+				"Open":         {"true"},
+				"MinPrice":     {"9.99"},
+				"Server":       {"10.0.0.1"},
+				"Filter.Owner": {"bob"},
+			},
+		},
+		"Zero values are empty and fall back to defaults": {
+			strct: &search{},
+			want: url.Values{
+				"q":            {""},
+				"Page":         {"1"},
+				"Sort":         {"newest"},
+				"Open":         {""},
+				"MinPrice":     {""},
+				"Server":       {""},
+				"Filter.Owner": {""},
+			},
+		},
+		"OmitEmpty leaves out empty values": {
+			strct: search{Query: "boots", Tags: []string{}},
+			opts:  []form_builder.Option{form_builder.OmitEmpty()},
+			want: url.Values{
+				"q":    {"boots"},
+				"Page": {"1"},
+				"Sort": {"newest"},
+			},
+		},
+		"Files are left out": {
+			strct: upload{Title: "Holiday"},
+			want:  url.Values{"Title": {"Holiday"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := form_builder.Values(tc.strct, tc.opts...)
+			if err != nil {
+				t.Fatalf("Values() err = %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Values() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValues_roundTrip(t *testing.T) {
+	price := 12.5
+	want := search{
+		Query:    "red shoes",
+		Page:     2,
+		Sort:     "price",
+		Tags:     []string{"sale"},
+		Open:     true,
+		MinPrice: &price,
+		Server:   net.ParseIP("2001:db8::1"),
+	}
+
+	values, err := form_builder.Values(want, form_builder.OmitEmpty())
+	if err != nil {
+		t.Fatalf("Values() err = %v; want nil", err)
+	}
+	var got search
+	errors, err := form_builder.Bind(values, &got)
+	if err != nil || len(errors) > 0 {
+		t.Fatalf("Bind() = %v, %v; want no errors", errors, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bind(Values()) = %+v; want %+v", got, want)
+	}
+}
+
+func TestValues_notStruct(t *testing.T) {
+	_, err := form_builder.Values("not a struct")
+	if err == nil {
+		t.Errorf("Values() err = nil; want an error")
+	}
+}